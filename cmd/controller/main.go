@@ -20,14 +20,20 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
 	ovhcloud "github.com/ovh/karpenter-provider-ovhcloud/pkg/cloudprovider"
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/controllers/allocatable"
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/controllers/interruption"
 	"github.com/ovh/karpenter-provider-ovhcloud/pkg/controllers/nodeclass"
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/controllers/nodehealth"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider/overlay"
 	"sigs.k8s.io/karpenter/pkg/controllers"
 	"sigs.k8s.io/karpenter/pkg/controllers/state"
@@ -87,6 +93,15 @@ func main() {
 		logger.Error(err, "failed creating OVH client")
 		os.Exit(1)
 	}
+	ovhClient.
+		WithObserver(ovhcloud.APIMetricsObserver{}).
+		WithRateLimitConfig(client.RateLimitConfig{
+			ReadQPS:     getEnvFloatOrDefault(ctx, "OVH_API_READ_QPS", client.DefaultRateLimitConfig.ReadQPS),
+			ReadBurst:   getEnvIntOrDefault(ctx, "OVH_API_READ_BURST", client.DefaultRateLimitConfig.ReadBurst),
+			WriteQPS:    getEnvFloatOrDefault(ctx, "OVH_API_WRITE_QPS", client.DefaultRateLimitConfig.WriteQPS),
+			WriteBurst:  getEnvIntOrDefault(ctx, "OVH_API_WRITE_BURST", client.DefaultRateLimitConfig.WriteBurst),
+			MaxInFlight: getEnvIntOrDefault(ctx, "OVH_API_MAX_IN_FLIGHT", client.DefaultRateLimitConfig.MaxInFlight),
+		})
 
 	// Auto-detect region from MKS cluster if not explicitly set
 	if region == "" {
@@ -100,8 +115,28 @@ func main() {
 		logger.Info("Using configured region", "region", region)
 	}
 
+	// Pricing client shared by the cloud provider and the cost metrics
+	// The catalog is revalidated in the background so that pricing lookups never block on a download
+	pricingClient := client.NewPricingClient("FR").
+		WithObserver(ovhcloud.PricingMetricsObserver{}).
+		WithRefreshInterval(getEnvDurationOrDefault(ctx, "PRICING_REFRESH_INTERVAL", client.DefaultPricingRefreshInterval))
+	ovhcloud.RegisterPricingMetrics(pricingClient)
+	if err := op.Manager.Add(pricingClient); err != nil {
+		logger.Error(err, "failed adding pricing refresher")
+		os.Exit(1)
+	}
+
+	// Load the catalog before instance types are built, so that they start with catalog prices
+	// The load is bounded: an unreachable catalog must not hold startup, prices are estimated until it loads
+	catalogCtx, cancel := context.WithTimeout(ctx, client.DefaultPricingLoadTimeout)
+	if err := pricingClient.Refresh(catalogCtx); err != nil {
+		logger.Error(err, "failed loading pricing catalog, using estimated prices until it is refreshed")
+	}
+	cancel()
+
 	// Construct instance types from OVH flavors
-	instanceTypes, err := ovhcloud.ConstructInstanceTypes(ctx, ovhClient)
+	// Offerings are repriced from the pricing catalog each time instance types are read
+	instanceTypes, err := ovhcloud.ConstructInstanceTypesWithPricing(ctx, ovhClient, pricingClient)
 	if err != nil {
 		logger.Error(err, "failed constructing instance types")
 		os.Exit(1)
@@ -109,13 +144,94 @@ func main() {
 
 	logger.Info("Loaded instance types", "count", len(instanceTypes))
 
+	// Allocatable resources observed on registered Nodes, persisted in the controller namespace
+	// The leader records them, every replica re-reads them periodically
+	allocatableCache := ovhcloud.NewAllocatableCache(op.GetClient(), getEnvOrDefault("SYSTEM_NAMESPACE", "karpenter"))
+	if err := op.Manager.Add(allocatableCache); err != nil {
+		logger.Error(err, "failed adding allocatable cache")
+		os.Exit(1)
+	}
+
+	// Snapshot of the Karpenter-managed pools and nodes shared by the cloud provider and the pool metrics
+	inventoryRefreshInterval := getEnvDurationOrDefault(ctx, "INVENTORY_REFRESH_INTERVAL", ovhcloud.DefaultInventoryRefreshInterval)
+	inventory := ovhcloud.NewInventory(ovhClient, inventoryRefreshInterval)
+	if err := op.Manager.Add(inventory); err != nil {
+		logger.Error(err, "failed adding pool inventory")
+		os.Exit(1)
+	}
+
+	// Node conditions repaired for the nodes of NodeClasses that set no repair policies, including MKS
+	// nodes reported in ERROR
+	repairPolicies := ovhcloud.DefaultRepairPolicies
+	if spec := os.Getenv("REPAIR_POLICIES"); spec != "" {
+		parsed, err := ovhcloud.ParseRepairPolicies(spec)
+		if err != nil {
+			logger.Error(err, "invalid REPAIR_POLICIES")
+			os.Exit(1)
+		}
+		repairPolicies = parsed
+	}
+
 	// Create cloud provider
-	overlayUndecoratedCloudProvider := ovhcloud.NewCloudProvider(ctx, op.GetClient(), ovhClient, instanceTypes)
+	overlayUndecoratedCloudProvider := ovhcloud.NewCloudProviderWithPricing(ctx, op.GetClient(), ovhClient, pricingClient, inventory, instanceTypes).
+		WithAllocatableCache(allocatableCache)
 	cloudProvider := overlay.Decorate(overlayUndecoratedCloudProvider, op.GetClient(), op.InstanceTypeStore)
 	clusterState := state.NewCluster(op.Clock, op.GetClient(), cloudProvider)
 
+	// Export Karpenter-managed pool inventory metrics
+	poolCollector := ovhcloud.NewPoolCollector(inventory,
+		getEnvDurationOrDefault(ctx, "POOL_METRICS_INTERVAL", ovhcloud.DefaultPoolCollectorInterval),
+		getEnvDurationOrDefault(ctx, "POOL_STUCK_THRESHOLD", ovhcloud.DefaultPoolStuckThreshold))
+	metrics.Registry.MustRegister(poolCollector)
+	if err := op.Manager.Add(poolCollector); err != nil {
+		logger.Error(err, "failed adding pool collector")
+		os.Exit(1)
+	}
+
+	// Export the estimated spend of Karpenter-managed nodes
+	costCollector := ovhcloud.NewCostCollector(overlayUndecoratedCloudProvider,
+		getEnvDurationOrDefault(ctx, "COST_METRICS_INTERVAL", ovhcloud.DefaultCostCollectorInterval))
+	if err := op.Manager.Add(costCollector); err != nil {
+		logger.Error(err, "failed adding cost collector")
+		os.Exit(1)
+	}
+
+	// Delete the nodes of launches abandoned before MKS listed them
+	launchReaper := ovhcloud.NewLaunchReaper(overlayUndecoratedCloudProvider, ovhcloud.DefaultLaunchReaperInterval)
+	if err := op.Manager.Add(launchReaper); err != nil {
+		logger.Error(err, "failed adding launch reaper")
+		os.Exit(1)
+	}
+
+	// Surface MKS nodes in ERROR as a Node condition and request the repair of the nodes matching the
+	// repair policies of their NodeClass
+	nodeHealthController := nodehealth.NewController(op.GetClient(), inventory, inventoryRefreshInterval, repairPolicies)
+	if err := op.Manager.Add(nodeHealthController); err != nil {
+		logger.Error(err, "failed adding node health controller")
+		os.Exit(1)
+	}
+
+	// Replace Karpenter nodes ahead of host maintenance and instance migrations
+	if getEnvOrDefault("INTERRUPTION_ENABLED", "true") == "true" {
+		sources := []interruption.Source{interruption.NewInstanceStatusSource(ovhClient)}
+		if eventsURL := os.Getenv("INTERRUPTION_EVENTS_URL"); eventsURL != "" {
+			sources = append(sources, interruption.NewHTTPSource(eventsURL))
+		}
+		interruptionController := interruption.NewController(op.GetClient(),
+			getEnvDurationOrDefault(ctx, "INTERRUPTION_POLL_INTERVAL", interruption.DefaultPollInterval),
+			getEnvDurationOrDefault(ctx, "INTERRUPTION_LEAD_TIME", interruption.DefaultLeadTime),
+			sources...)
+		if err := op.Manager.Add(interruptionController); err != nil {
+			logger.Error(err, "failed adding interruption controller")
+			os.Exit(1)
+		}
+	}
+
 	// Create OVHNodeClass controller
-	ovhNodeClassController := nodeclass.NewController(op.GetClient())
+	ovhNodeClassController := nodeclass.NewController(op.GetClient(), ovhClient)
+
+	// Create the controller learning allocatable resources from registered Nodes
+	allocatableController := allocatable.NewController(allocatableCache)
 
 	// Get base controllers and append OVHNodeClass controller
 	baseControllers := controllers.NewControllers(
@@ -131,7 +247,7 @@ func main() {
 	)
 
 	op.
-		WithControllers(ctx, append(baseControllers, ovhNodeClassController)...).
+		WithControllers(ctx, append(baseControllers, ovhNodeClassController, allocatableController)...).
 		Start(ctx)
 }
 
@@ -142,6 +258,53 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvDurationOrDefault parses a duration from the environment, falling back to the default when unset or invalid
+// Durations are intervals and timeouts, so zero and negative values are invalid
+func getEnvDurationOrDefault(ctx context.Context, key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "invalid duration, using default", "key", key, "default", defaultValue)
+		return defaultValue
+	}
+	if duration <= 0 {
+		log.FromContext(ctx).Error(fmt.Errorf("duration %s is not positive", duration), "invalid duration, using default", "key", key, "default", defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+// getEnvFloatOrDefault parses a float from the environment, falling back to the default when unset or invalid
+func getEnvFloatOrDefault(ctx context.Context, key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "invalid number, using default", "key", key, "default", defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvIntOrDefault parses an integer from the environment, falling back to the default when unset or invalid
+func getEnvIntOrDefault(ctx context.Context, key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "invalid integer, using default", "key", key, "default", defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
 // detectKubeIDFromNodes attempts to detect the MKS cluster ID from node annotations
 func detectKubeIDFromNodes(ctx context.Context, kubeClient ctrlclient.Client) (string, error) {
 	var nodes corev1.NodeList
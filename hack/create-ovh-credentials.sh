@@ -57,7 +57,7 @@ case "$OVH_ENDPOINT" in
 esac
 
 # Build the pre-filled URL
-PREFILLED_URL="${API_BASE}/createToken/?GET=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}&GET=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}/nodepool&GET=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}/nodepool/*&POST=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}/nodepool&PUT=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}/nodepool/*&DELETE=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}/nodepool/*&GET=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}/flavors&GET=/cloud/project/${OVH_SERVICE_NAME}/capabilities/kube/*"
+PREFILLED_URL="${API_BASE}/createToken/?GET=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}&GET=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}/nodepool&GET=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}/nodepool/*&POST=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}/nodepool&PUT=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}/nodepool/*&DELETE=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}/nodepool/*&DELETE=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}/node/*&GET=/cloud/project/${OVH_SERVICE_NAME}/kube/${OVH_KUBE_ID}/flavors&GET=/cloud/project/${OVH_SERVICE_NAME}/capabilities/kube/*&GET=/cloud/project/${OVH_SERVICE_NAME}/flavor&GET=/cloud/project/${OVH_SERVICE_NAME}/quota&GET=/cloud/project/${OVH_SERVICE_NAME}/instance"
 
 echo ""
 echo -e "${YELLOW}Configuration:${NC}"
@@ -99,8 +99,10 @@ echo "════════════════════════
 echo ""
 echo -e "${GREEN}Permissions granted (cluster-scoped only):${NC}"
 echo "  - GET/POST/PUT/DELETE on node pools"
+echo "  - DELETE on nodes"
 echo "  - GET cluster info and flavors"
 echo "  - GET MKS capabilities"
+echo "  - GET project flavors, quotas and instances"
 echo ""
 echo -e "${YELLOW}This key CANNOT:${NC}"
 echo "  - Access other clusters in the project"
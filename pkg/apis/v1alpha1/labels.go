@@ -17,26 +17,63 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis"
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 )
 
 const (
 	// Instance labels
-	LabelInstanceCategory = apis.Group + "/instance-category" // b2, c2, r2, t2, etc.
-	LabelInstanceCPU      = apis.Group + "/instance-cpu"
-	LabelInstanceMemory   = apis.Group + "/instance-memory"
-	LabelInstanceFamily   = apis.Group + "/instance-family"
-	LabelInstanceSize     = apis.Group + "/instance-size"
+	LabelInstanceCategory   = apis.Group + "/instance-category" // b2, c2, r2, t2, etc.
+	LabelInstanceCPU        = apis.Group + "/instance-cpu"      // vCPU count
+	LabelInstanceMemory     = apis.Group + "/instance-memory"   // in MiB
+	LabelInstanceFamily     = apis.Group + "/instance-family"   // b3, c3, t1-le, etc.
+	LabelInstanceSize       = apis.Group + "/instance-size"     // 8, 16, 32, etc.
+	LabelInstanceGeneration = apis.Group + "/instance-generation"
+	LabelInstanceLocalNVMe  = apis.Group + "/instance-local-nvme" // number of local NVMe disks
+
+	// GPU labels (gpu-count is set on every flavor, the others only on GPU flavors)
+	LabelGPUCount        = apis.Group + "/gpu-count"
+	LabelGPUName         = apis.Group + "/gpu-name"         // v100, a100, l40s, etc.
+	LabelGPUManufacturer = apis.Group + "/gpu-manufacturer" // nvidia
+	LabelGPUMemory       = apis.Group + "/gpu-memory"       // per GPU, in MiB
 
 	// Pool tracking
 	LabelPoolID   = apis.Group + "/pool-id"
 	LabelPoolName = apis.Group + "/pool-name"
 
+	// Pool metadata, recorded in the pool template labels so that pools map back to their
+	// NodeClass and billing mode without parsing pool names
+	LabelPoolNodeClass     = apis.Group + "/nodeclass"
+	LabelPoolMonthlyBilled = apis.Group + "/monthly-billed"
+
+	// LabelPoolHash is the hash of the NodeClass spec and NodePool template a pool was created
+	// for; it is also the suffix of the pool name
+	LabelPoolHash = apis.Group + "/pool-hash"
+	// LabelPoolNodeClassHash is the hash of the NodeClass spec alone, to tell NodeClass drift
+	// from NodePool template drift
+	LabelPoolNodeClassHash = apis.Group + "/nodeclass-hash"
+
 	// Annotations for tracking NodeClaim to Node mapping
 	AnnotationOVHPoolID   = apis.Group + "/pool-id"
 	AnnotationOVHNodeID   = apis.Group + "/node-id"
 	AnnotationOVHNodeName = apis.Group + "/node-name"
+
+	// TaintKeyInterruption marks nodes being replaced ahead of a host maintenance or an instance migration
+	TaintKeyInterruption = apis.Group + "/interruption"
+	// AnnotationInterruption marks the NodeClaims of those nodes with the event kind; they are reported
+	// as drifted so that Karpenter replaces them within the NodePool disruption budgets
+	AnnotationInterruption = apis.Group + "/interruption"
+
+	// NodeConditionTypeMKSNodeError is set on Nodes whose MKS node is reported in ERROR
+	NodeConditionTypeMKSNodeError corev1.NodeConditionType = "MKSNodeError"
+	// NodeConditionTypeRepairRequested is set on Nodes with a condition that lasted longer than the
+	// toleration of the repair policies of their NodeClass
+	NodeConditionTypeRepairRequested corev1.NodeConditionType = "RepairRequested"
+
+	// AnnotationOVHMonthlyBilled records whether the backing pool is billed monthly ("true") or hourly ("false")
+	AnnotationOVHMonthlyBilled = apis.Group + "/monthly-billed"
 )
 
 func init() {
@@ -47,5 +84,11 @@ func init() {
 		LabelInstanceMemory,
 		LabelInstanceFamily,
 		LabelInstanceSize,
+		LabelInstanceGeneration,
+		LabelInstanceLocalNVMe,
+		LabelGPUCount,
+		LabelGPUName,
+		LabelGPUManufacturer,
+		LabelGPUMemory,
 	)
 }
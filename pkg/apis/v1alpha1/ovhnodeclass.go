@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -64,6 +65,72 @@ type OVHNodeClassSpec struct {
 	// Tags are key-value pairs applied to the node pools
 	// +optional
 	Tags map[string]string `json:"tags,omitempty"`
+
+	// Overhead overrides the resources Karpenter expects MKS to reserve on each node
+	// Resources not set here are computed from the flavor's vCPU, memory and disk
+	// +optional
+	Overhead *OverheadConfiguration `json:"overhead,omitempty"`
+
+	// UpgradePolicy defines what happens to nodes MKS reports as not running the cluster's Kubernetes version
+	// Replace reports them as drifted so Karpenter replaces them within the NodePool disruption budgets
+	// +kubebuilder:validation:Enum=Replace;Ignore
+	// +kubebuilder:default:=Ignore
+	// +optional
+	UpgradePolicy UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+	// LaunchTimeout is how long a launched node may take to become READY in MKS
+	// Nodes still not READY after this, or reported in ERROR, are deleted and the launch is retried
+	// with another flavor or zone. Defaults to 10m.
+	// +optional
+	LaunchTimeout *metav1.Duration `json:"launchTimeout,omitempty"`
+
+	// RepairPolicies replaces the controller's repair policies for the nodes of this NodeClass
+	// e.g. a longer Ready toleration for GPU nodes whose drivers take time to initialize. Nodes MKS
+	// reports in ERROR are repaired unless a policy sets another toleration for MKSNodeError.
+	// +optional
+	RepairPolicies []RepairPolicy `json:"repairPolicies,omitempty"`
+}
+
+// RepairPolicy is a node condition Karpenter repairs once it lasted longer than its toleration
+type RepairPolicy struct {
+	// ConditionType is the type of the node condition, e.g. Ready or KernelDeadlock
+	// +kubebuilder:validation:Required
+	ConditionType corev1.NodeConditionType `json:"conditionType"`
+
+	// ConditionStatus is the status of the condition that marks the node unhealthy
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	// +kubebuilder:validation:Required
+	ConditionStatus corev1.ConditionStatus `json:"conditionStatus"`
+
+	// TolerationDuration is how long the condition is tolerated before the node is repaired
+	// +kubebuilder:validation:Required
+	TolerationDuration metav1.Duration `json:"tolerationDuration"`
+}
+
+// UpgradePolicy defines how outdated nodes are handled after a control-plane upgrade
+type UpgradePolicy string
+
+const (
+	// UpgradePolicyReplace replaces outdated nodes through drift
+	UpgradePolicyReplace UpgradePolicy = "Replace"
+	// UpgradePolicyIgnore leaves outdated nodes running
+	UpgradePolicyIgnore UpgradePolicy = "Ignore"
+)
+
+// OverheadConfiguration overrides the modelled node overhead used to compute allocatable resources
+// This does not change the kubelet configuration of MKS nodes, only Karpenter's scheduling simulation
+type OverheadConfiguration struct {
+	// KubeReserved is the amount of resources reserved for Kubernetes daemons (kubelet, container runtime)
+	// +optional
+	KubeReserved corev1.ResourceList `json:"kubeReserved,omitempty"`
+
+	// SystemReserved is the amount of resources reserved for OS system daemons
+	// +optional
+	SystemReserved corev1.ResourceList `json:"systemReserved,omitempty"`
+
+	// EvictionThreshold is the amount of resources kept free by kubelet hard eviction
+	// +optional
+	EvictionThreshold corev1.ResourceList `json:"evictionThreshold,omitempty"`
 }
 
 // OVHNodeClass is the Schema for the OVHNodeClass API
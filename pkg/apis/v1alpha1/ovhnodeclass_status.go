@@ -20,6 +20,10 @@ import (
 	"github.com/awslabs/operatorpkg/status"
 )
 
+// ConditionTypeAPIAvailable reports whether the OVHcloud API circuit breaker lets calls through
+// It is informational and does not gate readiness: launches fail fast on their own while the circuit is open
+const ConditionTypeAPIAvailable = "APIAvailable"
+
 // OVHNodeClassStatus contains the resolved state of the OVHNodeClass
 type OVHNodeClassStatus struct {
 	// Conditions contains signals for health and readiness
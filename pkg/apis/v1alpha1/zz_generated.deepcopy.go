@@ -22,6 +22,8 @@ package v1alpha1
 
 import (
 	"github.com/awslabs/operatorpkg/status"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -99,6 +101,21 @@ func (in *OVHNodeClassSpec) DeepCopyInto(out *OVHNodeClassSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Overhead != nil {
+		in, out := &in.Overhead, &out.Overhead
+		*out = new(OverheadConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LaunchTimeout != nil {
+		in, out := &in.LaunchTimeout, &out.LaunchTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RepairPolicies != nil {
+		in, out := &in.RepairPolicies, &out.RepairPolicies
+		*out = make([]RepairPolicy, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVHNodeClassSpec.
@@ -138,6 +155,58 @@ func (in *OVHNodeClassStatus) DeepCopy() *OVHNodeClassStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OverheadConfiguration) DeepCopyInto(out *OverheadConfiguration) {
+	*out = *in
+	if in.KubeReserved != nil {
+		in, out := &in.KubeReserved, &out.KubeReserved
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.SystemReserved != nil {
+		in, out := &in.SystemReserved, &out.SystemReserved
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.EvictionThreshold != nil {
+		in, out := &in.EvictionThreshold, &out.EvictionThreshold
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverheadConfiguration.
+func (in *OverheadConfiguration) DeepCopy() *OverheadConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(OverheadConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepairPolicy) DeepCopyInto(out *RepairPolicy) {
+	*out = *in
+	out.TolerationDuration = in.TolerationDuration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepairPolicy.
+func (in *RepairPolicy) DeepCopy() *RepairPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RepairPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretReference) DeepCopyInto(out *SecretReference) {
 	*out = *in
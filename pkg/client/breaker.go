@@ -0,0 +1,141 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+// ErrCircuitOpen is returned without calling the OVH API while the circuit breaker is open
+var ErrCircuitOpen = errors.New("OVH API circuit breaker is open")
+
+// CircuitBreakerConfig defines when the circuit breaker opens and for how long
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed attempts that opens the circuit
+	FailureThreshold int
+	// OpenDuration is how long calls fail fast before a single probe call is let through
+	OpenDuration time.Duration
+}
+
+// DefaultCircuitBreakerConfig opens the circuit after two fully retried calls failed in a row
+var DefaultCircuitBreakerConfig = CircuitBreakerConfig{
+	FailureThreshold: 8,
+	OpenDuration:     30 * time.Second,
+}
+
+// circuitBreaker stops calling the OVH API during sustained outages
+// Only retryable errors (rate limiting, server and network errors) count as failures,
+// client errors such as 404 prove the API is reachable
+type circuitBreaker struct {
+	config   CircuitBreakerConfig
+	observer APIObserver
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// newCircuitBreaker creates a closed circuit breaker; a non-positive threshold disables it
+func newCircuitBreaker(config CircuitBreakerConfig, observer APIObserver) *circuitBreaker {
+	return &circuitBreaker{
+		config:   config,
+		observer: observer,
+		state:    CircuitClosed,
+	}
+}
+
+// allow returns ErrCircuitOpen when the call must fail fast
+func (b *circuitBreaker) allow() error {
+	if b.config.FailureThreshold <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.config.OpenDuration {
+		b.transition(CircuitHalfOpen)
+	}
+	switch b.state {
+	case CircuitOpen:
+		return ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record updates the breaker with the result of an allowed attempt
+func (b *circuitBreaker) record(err error) {
+	if b.config.FailureThreshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if !isRetryableError(err) {
+		b.failures = 0
+		b.transition(CircuitClosed)
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.openedAt = time.Now()
+		b.transition(CircuitOpen)
+	}
+}
+
+// cancel releases an allowed attempt that never reached the API
+func (b *circuitBreaker) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// transition moves the breaker to a state and notifies the observer; callers must hold the lock
+func (b *circuitBreaker) transition(state string) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+	b.observer.ObserveCircuitState(state)
+}
+
+// State returns the current state of the breaker
+func (b *circuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.config.OpenDuration {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
@@ -18,9 +18,12 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
+	"net/http"
 	"strings"
 	"time"
 
@@ -80,13 +83,207 @@ func calculateBackoff(attempt int, config RetryConfig) time.Duration {
 	return time.Duration(backoff + jitter)
 }
 
+// APIObserver receives instrumentation events for OVH API calls
+// It lets callers record metrics without pkg/client depending on a metrics package
+type APIObserver interface {
+	// ObserveAPICall is called once per attempt with the HTTP status class (2xx, 4xx, 5xx or error)
+	ObserveAPICall(method, endpoint, status string, duration time.Duration)
+	// ObserveAPIRetry is called each time a failed attempt is retried
+	ObserveAPIRetry(method, endpoint string)
+	// ObserveAPIQueueWait is called once per attempt with the time spent waiting on the client-side rate limiter
+	ObserveAPIQueueWait(class string, wait time.Duration)
+	// ObserveCircuitState is called when the circuit breaker changes state (closed, open or half-open)
+	ObserveCircuitState(state string)
+}
+
+// noopObserver discards all instrumentation events
+type noopObserver struct{}
+
+func (noopObserver) ObserveAPICall(method, endpoint, status string, duration time.Duration) {}
+func (noopObserver) ObserveAPIRetry(method, endpoint string)                                {}
+func (noopObserver) ObserveAPIQueueWait(class string, wait time.Duration)                   {}
+func (noopObserver) ObserveCircuitState(state string)                                       {}
+
+// apiCall identifies an OVH API call for retries and instrumentation
+type apiCall struct {
+	Operation string
+	Method    string
+	Endpoint  string // path template without IDs, e.g. /cloud/project/{serviceName}/kube/{kubeId}/nodepool
+}
+
+// Endpoint templates used to label API metrics (IDs replaced by placeholders)
+const (
+	endpointCluster        = "/cloud/project/{serviceName}/kube/{kubeId}"
+	endpointNodePools      = "/cloud/project/{serviceName}/kube/{kubeId}/nodepool"
+	endpointNodePool       = "/cloud/project/{serviceName}/kube/{kubeId}/nodepool/{nodepoolId}"
+	endpointPoolNodes      = "/cloud/project/{serviceName}/kube/{kubeId}/nodepool/{nodepoolId}/nodes"
+	endpointNode           = "/cloud/project/{serviceName}/kube/{kubeId}/node/{nodeId}"
+	endpointClusterFlavors = "/cloud/project/{serviceName}/kube/{kubeId}/flavors"
+	endpointKubeRegions    = "/cloud/project/{serviceName}/capabilities/kube/regions"
+	endpointKubeFlavors    = "/cloud/project/{serviceName}/capabilities/kube/flavors"
+	endpointProjectFlavors = "/cloud/project/{serviceName}/flavor"
+	endpointQuota          = "/cloud/project/{serviceName}/quota"
+	endpointInstances      = "/cloud/project/{serviceName}/instance"
+)
+
+// API calls made by OVHClient
+var (
+	callListNodePools      = apiCall{Operation: "ListNodePools", Method: http.MethodGet, Endpoint: endpointNodePools}
+	callGetNodePool        = apiCall{Operation: "GetNodePool", Method: http.MethodGet, Endpoint: endpointNodePool}
+	callCreateNodePool     = apiCall{Operation: "CreateNodePool", Method: http.MethodPost, Endpoint: endpointNodePools}
+	callUpdateNodePool     = apiCall{Operation: "UpdateNodePool", Method: http.MethodPut, Endpoint: endpointNodePool}
+	callDeleteNodePool     = apiCall{Operation: "DeleteNodePool", Method: http.MethodDelete, Endpoint: endpointNodePool}
+	callListPoolNodes      = apiCall{Operation: "ListPoolNodes", Method: http.MethodGet, Endpoint: endpointPoolNodes}
+	callListFlavors        = apiCall{Operation: "ListFlavors", Method: http.MethodGet, Endpoint: endpointClusterFlavors}
+	callListKubeRegions    = apiCall{Operation: "ListKubeRegions", Method: http.MethodGet, Endpoint: endpointKubeRegions}
+	callListKubeFlavors    = apiCall{Operation: "ListKubeFlavors", Method: http.MethodGet, Endpoint: endpointKubeFlavors}
+	callListProjectFlavors = apiCall{Operation: "ListProjectFlavors", Method: http.MethodGet, Endpoint: endpointProjectFlavors}
+	callGetCluster         = apiCall{Operation: "GetCluster", Method: http.MethodGet, Endpoint: endpointCluster}
+	callDeleteNode         = apiCall{Operation: "DeleteNode", Method: http.MethodDelete, Endpoint: endpointNode}
+	callListQuotas         = apiCall{Operation: "ListQuotas", Method: http.MethodGet, Endpoint: endpointQuota}
+	callListInstances      = apiCall{Operation: "ListInstances", Method: http.MethodGet, Endpoint: endpointInstances}
+)
+
+// statusClass returns the HTTP status class of an API call result
+func statusClass(err error) string {
+	if err == nil {
+		return "2xx"
+	}
+	var apiErr *ovh.APIError
+	if errors.As(err, &apiErr) && apiErr.Code > 0 {
+		return fmt.Sprintf("%dxx", apiErr.Code/100)
+	}
+	return "error"
+}
+
+// IsNotFound returns true if the OVH API reported the resource as not found
+func IsNotFound(err error) bool {
+	var apiErr *ovh.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
+
+// IsConflict returns true if the OVH API rejected a write because the resource already exists
+func IsConflict(err error) bool {
+	var apiErr *ovh.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusConflict
+}
+
+// IsAmbiguous returns true if a failed write may still have been applied by the OVH API,
+// e.g. a server error, a timeout or a connection closed after the request was sent
+func IsAmbiguous(err error) bool {
+	if err == nil || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	var apiErr *ovh.APIError
+	if errors.As(err, &apiErr) && apiErr.Code > 0 {
+		return apiErr.Code >= http.StatusInternalServerError
+	}
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		isRetryableError(err)
+}
+
+// capacityErrorClasses are the last segments of the OVH API error classes (e.g.
+// "Client::Forbidden::QuotaExceeded") reporting a lack of capacity or quota
+var capacityErrorClasses = map[string]bool{
+	"QuotaExceeded":        true,
+	"QuotaReached":         true,
+	"InsufficientCapacity": true,
+	"InsufficientQuota":    true,
+	"OutOfStock":           true,
+}
+
+// capacityErrorMarkers are substrings of OVH API error messages reporting a lack of capacity or quota
+// They are a fallback for errors without a capacity class, e.g. quota errors passed through from
+// OpenStack, and are only matched on rejected launch requests that are not permission errors.
+var capacityErrorMarkers = []string{
+	"quota",
+	"insufficient",
+	"not enough",
+	"no more",
+	"capacity",
+	"out of stock",
+}
+
+// permissionErrorMarkers are substrings of OVH API error messages reporting a missing grant,
+// whose path may itself mention a quota (e.g. a call to the quota endpoint that was not granted)
+var permissionErrorMarkers = []string{
+	"not been granted",
+	"not allowed",
+	"invalid credential",
+}
+
+// IsCapacityError returns true if the OVH API rejected a launch because the flavor is out of
+// capacity in the zone or the project ran out of quota
+// Only rejections (400, 403 and 412) qualify: authentication, missing resources, conflicts,
+// throttling and server errors never report a lack of capacity.
+func IsCapacityError(err error) bool {
+	var apiErr *ovh.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Code {
+	case http.StatusBadRequest, http.StatusForbidden, http.StatusPreconditionFailed:
+	default:
+		return false
+	}
+
+	class := apiErr.Class
+	if idx := strings.LastIndex(class, "::"); idx >= 0 {
+		class = class[idx+len("::"):]
+	}
+	if capacityErrorClasses[class] {
+		return true
+	}
+
+	message := strings.ToLower(apiErr.Message)
+	for _, marker := range permissionErrorMarkers {
+		if strings.Contains(message, marker) {
+			return false
+		}
+	}
+	for _, marker := range capacityErrorMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// attempt runs a single attempt of an API call once the circuit breaker and the rate limiter
+// admit it, and reports it to the observer
+func (c *OVHClient) attempt(ctx context.Context, call apiCall, fn func() error) error {
+	if err := c.breaker.allow(); err != nil {
+		return fmt.Errorf("%s: %w", call.Operation, err)
+	}
+
+	release, wait, err := c.limiter.acquire(ctx, call)
+	c.observer.ObserveAPIQueueWait(endpointClass(call.Method), wait)
+	if err != nil {
+		// Not an API failure, let another probe through if this attempt was one
+		c.breaker.cancel()
+		return fmt.Errorf("waiting for rate limiter: %w", err)
+	}
+	defer release()
+
+	start := time.Now()
+	err = fn()
+	c.observer.ObserveAPICall(call.Method, call.Endpoint, statusClass(err), time.Since(start))
+	c.breaker.record(err)
+	return err
+}
+
 // retryableAPICall wraps an API call with retry logic
-func retryableAPICall[T any](ctx context.Context, config RetryConfig, operation string, fn func() (T, error)) (T, error) {
+func retryableAPICall[T any](ctx context.Context, c *OVHClient, call apiCall, fn func() (T, error)) (T, error) {
 	var result T
 	var lastErr error
+	config := c.retryConfig
 
 	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
-		result, lastErr = fn()
+		lastErr = c.attempt(ctx, call, func() error {
+			var err error
+			result, err = fn()
+			return err
+		})
 		if lastErr == nil {
 			return result, nil
 		}
@@ -96,6 +293,7 @@ func retryableAPICall[T any](ctx context.Context, config RetryConfig, operation
 		}
 
 		if attempt < config.MaxRetries {
+			c.observer.ObserveAPIRetry(call.Method, call.Endpoint)
 			backoff := calculateBackoff(attempt, config)
 			select {
 			case <-ctx.Done():
@@ -106,15 +304,16 @@ func retryableAPICall[T any](ctx context.Context, config RetryConfig, operation
 		}
 	}
 
-	return result, fmt.Errorf("%s failed after %d retries: %w", operation, config.MaxRetries+1, lastErr)
+	return result, fmt.Errorf("%s failed after %d retries: %w", call.Operation, config.MaxRetries+1, lastErr)
 }
 
 // retryableVoidCall wraps a void API call with retry logic
-func retryableVoidCall(ctx context.Context, config RetryConfig, operation string, fn func() error) error {
+func retryableVoidCall(ctx context.Context, c *OVHClient, call apiCall, fn func() error) error {
 	var lastErr error
+	config := c.retryConfig
 
 	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
-		lastErr = fn()
+		lastErr = c.attempt(ctx, call, fn)
 		if lastErr == nil {
 			return nil
 		}
@@ -124,6 +323,7 @@ func retryableVoidCall(ctx context.Context, config RetryConfig, operation string
 		}
 
 		if attempt < config.MaxRetries {
+			c.observer.ObserveAPIRetry(call.Method, call.Endpoint)
 			backoff := calculateBackoff(attempt, config)
 			select {
 			case <-ctx.Done():
@@ -134,7 +334,7 @@ func retryableVoidCall(ctx context.Context, config RetryConfig, operation string
 		}
 	}
 
-	return fmt.Errorf("%s failed after %d retries: %w", operation, config.MaxRetries+1, lastErr)
+	return fmt.Errorf("%s failed after %d retries: %w", call.Operation, config.MaxRetries+1, lastErr)
 }
 
 // OVHClient wraps the OVH API client for Kubernetes operations
@@ -144,6 +344,9 @@ type OVHClient struct {
 	kubeID      string
 	region      string
 	retryConfig RetryConfig
+	limiter     *rateLimiter
+	breaker     *circuitBreaker
+	observer    APIObserver
 }
 
 // NewOVHClient creates a new OVH API client
@@ -164,6 +367,9 @@ func NewOVHClient(creds *Credentials, serviceName, kubeID, region string) (*OVHC
 		kubeID:      kubeID,
 		region:      region,
 		retryConfig: DefaultRetryConfig,
+		limiter:     newRateLimiter(DefaultRateLimitConfig),
+		breaker:     newCircuitBreaker(DefaultCircuitBreakerConfig, noopObserver{}),
+		observer:    noopObserver{},
 	}, nil
 }
 
@@ -173,6 +379,30 @@ func (c *OVHClient) WithRetryConfig(config RetryConfig) *OVHClient {
 	return c
 }
 
+// WithRateLimitConfig sets custom client-side rate limits
+func (c *OVHClient) WithRateLimitConfig(config RateLimitConfig) *OVHClient {
+	c.limiter = newRateLimiter(config)
+	return c
+}
+
+// WithCircuitBreakerConfig sets custom circuit breaker thresholds
+func (c *OVHClient) WithCircuitBreakerConfig(config CircuitBreakerConfig) *OVHClient {
+	c.breaker = newCircuitBreaker(config, c.observer)
+	return c
+}
+
+// WithObserver sets the observer notified of every API call attempt
+func (c *OVHClient) WithObserver(observer APIObserver) *OVHClient {
+	c.observer = observer
+	c.breaker.observer = observer
+	return c
+}
+
+// CircuitState returns the state of the circuit breaker (closed, open or half-open)
+func (c *OVHClient) CircuitState() string {
+	return c.breaker.State()
+}
+
 // basePath returns the base API path for the cluster
 func (c *OVHClient) basePath() string {
 	return fmt.Sprintf("/cloud/project/%s/kube/%s", c.serviceName, c.kubeID)
@@ -181,7 +411,7 @@ func (c *OVHClient) basePath() string {
 // ListNodePools returns all node pools in the cluster
 func (c *OVHClient) ListNodePools(ctx context.Context) ([]NodePool, error) {
 	path := fmt.Sprintf("%s/nodepool", c.basePath())
-	return retryableAPICall(ctx, c.retryConfig, "ListNodePools", func() ([]NodePool, error) {
+	return retryableAPICall(ctx, c, callListNodePools, func() ([]NodePool, error) {
 		var pools []NodePool
 		if err := c.client.GetWithContext(ctx, path, &pools); err != nil {
 			return nil, fmt.Errorf("listing node pools: %w", err)
@@ -193,7 +423,7 @@ func (c *OVHClient) ListNodePools(ctx context.Context) ([]NodePool, error) {
 // GetNodePool returns a specific node pool by ID
 func (c *OVHClient) GetNodePool(ctx context.Context, poolID string) (*NodePool, error) {
 	path := fmt.Sprintf("%s/nodepool/%s", c.basePath(), poolID)
-	return retryableAPICall(ctx, c.retryConfig, "GetNodePool", func() (*NodePool, error) {
+	return retryableAPICall(ctx, c, callGetNodePool, func() (*NodePool, error) {
 		var pool NodePool
 		if err := c.client.GetWithContext(ctx, path, &pool); err != nil {
 			return nil, fmt.Errorf("getting node pool %s: %w", poolID, err)
@@ -203,21 +433,25 @@ func (c *OVHClient) GetNodePool(ctx context.Context, poolID string) (*NodePool,
 }
 
 // CreateNodePool creates a new node pool
+// Creating a pool is not idempotent, so the call is attempted once: a retry after an ambiguous
+// failure could conflict with the pool created by the first attempt. Callers resolve ambiguous
+// failures and conflicts by looking the pool up by name (see IsAmbiguous and IsConflict).
 func (c *OVHClient) CreateNodePool(ctx context.Context, req *CreateNodePoolRequest) (*NodePool, error) {
 	path := fmt.Sprintf("%s/nodepool", c.basePath())
-	return retryableAPICall(ctx, c.retryConfig, "CreateNodePool", func() (*NodePool, error) {
-		var pool NodePool
-		if err := c.client.PostWithContext(ctx, path, req, &pool); err != nil {
-			return nil, fmt.Errorf("creating node pool: %w", err)
-		}
-		return &pool, nil
+	var pool NodePool
+	err := c.attempt(ctx, callCreateNodePool, func() error {
+		return c.client.PostWithContext(ctx, path, req, &pool)
 	})
+	if err != nil {
+		return nil, fmt.Errorf("creating node pool: %w", err)
+	}
+	return &pool, nil
 }
 
 // UpdateNodePool updates a node pool (mainly for scaling)
 func (c *OVHClient) UpdateNodePool(ctx context.Context, poolID string, req *UpdateNodePoolRequest) (*NodePool, error) {
 	path := fmt.Sprintf("%s/nodepool/%s", c.basePath(), poolID)
-	return retryableAPICall(ctx, c.retryConfig, "UpdateNodePool", func() (*NodePool, error) {
+	return retryableAPICall(ctx, c, callUpdateNodePool, func() (*NodePool, error) {
 		var pool NodePool
 		if err := c.client.PutWithContext(ctx, path, req, &pool); err != nil {
 			return nil, fmt.Errorf("updating node pool %s: %w", poolID, err)
@@ -229,7 +463,7 @@ func (c *OVHClient) UpdateNodePool(ctx context.Context, poolID string, req *Upda
 // DeleteNodePool deletes a node pool
 func (c *OVHClient) DeleteNodePool(ctx context.Context, poolID string) error {
 	path := fmt.Sprintf("%s/nodepool/%s", c.basePath(), poolID)
-	return retryableVoidCall(ctx, c.retryConfig, "DeleteNodePool", func() error {
+	return retryableVoidCall(ctx, c, callDeleteNodePool, func() error {
 		if err := c.client.DeleteWithContext(ctx, path, nil); err != nil {
 			return fmt.Errorf("deleting node pool %s: %w", poolID, err)
 		}
@@ -240,7 +474,7 @@ func (c *OVHClient) DeleteNodePool(ctx context.Context, poolID string) error {
 // ListPoolNodes returns all nodes in a specific pool
 func (c *OVHClient) ListPoolNodes(ctx context.Context, poolID string) ([]Node, error) {
 	path := fmt.Sprintf("%s/nodepool/%s/nodes", c.basePath(), poolID)
-	return retryableAPICall(ctx, c.retryConfig, "ListPoolNodes", func() ([]Node, error) {
+	return retryableAPICall(ctx, c, callListPoolNodes, func() ([]Node, error) {
 		var nodes []Node
 		if err := c.client.GetWithContext(ctx, path, &nodes); err != nil {
 			return nil, fmt.Errorf("listing nodes in pool %s: %w", poolID, err)
@@ -252,7 +486,7 @@ func (c *OVHClient) ListPoolNodes(ctx context.Context, poolID string) ([]Node, e
 // ListFlavors returns available flavors for the cluster
 func (c *OVHClient) ListFlavors(ctx context.Context) ([]Flavor, error) {
 	path := fmt.Sprintf("%s/flavors", c.basePath())
-	return retryableAPICall(ctx, c.retryConfig, "ListFlavors", func() ([]Flavor, error) {
+	return retryableAPICall(ctx, c, callListFlavors, func() ([]Flavor, error) {
 		var flavors []Flavor
 		if err := c.client.GetWithContext(ctx, path, &flavors); err != nil {
 			return nil, fmt.Errorf("listing flavors: %w", err)
@@ -284,7 +518,7 @@ func (c *OVHClient) capabilitiesBasePath() string {
 // ListKubeRegions returns all available MKS regions for the project
 func (c *OVHClient) ListKubeRegions(ctx context.Context) ([]string, error) {
 	path := fmt.Sprintf("%s/regions", c.capabilitiesBasePath())
-	return retryableAPICall(ctx, c.retryConfig, "ListKubeRegions", func() ([]string, error) {
+	return retryableAPICall(ctx, c, callListKubeRegions, func() ([]string, error) {
 		var regions []string
 		if err := c.client.GetWithContext(ctx, path, &regions); err != nil {
 			return nil, fmt.Errorf("listing kube regions: %w", err)
@@ -296,7 +530,7 @@ func (c *OVHClient) ListKubeRegions(ctx context.Context) ([]string, error) {
 // ListKubeFlavors returns available MKS flavors for a specific region from the capabilities API
 func (c *OVHClient) ListKubeFlavors(ctx context.Context, region string) ([]KubeFlavorCapability, error) {
 	path := fmt.Sprintf("%s/flavors?region=%s", c.capabilitiesBasePath(), region)
-	return retryableAPICall(ctx, c.retryConfig, "ListKubeFlavors", func() ([]KubeFlavorCapability, error) {
+	return retryableAPICall(ctx, c, callListKubeFlavors, func() ([]KubeFlavorCapability, error) {
 		var flavors []KubeFlavorCapability
 		if err := c.client.GetWithContext(ctx, path, &flavors); err != nil {
 			return nil, fmt.Errorf("listing kube flavors for region %s: %w", region, err)
@@ -305,10 +539,56 @@ func (c *OVHClient) ListKubeFlavors(ctx context.Context, region string) ([]KubeF
 	})
 }
 
+// ListProjectFlavors returns the Public Cloud instance flavors for a region
+// API: GET /cloud/project/{serviceName}/flavor?region={region}
+func (c *OVHClient) ListProjectFlavors(ctx context.Context, region string) ([]ProjectFlavor, error) {
+	path := fmt.Sprintf("/cloud/project/%s/flavor?region=%s", c.serviceName, region)
+	return retryableAPICall(ctx, c, callListProjectFlavors, func() ([]ProjectFlavor, error) {
+		var flavors []ProjectFlavor
+		if err := c.client.GetWithContext(ctx, path, &flavors); err != nil {
+			return nil, fmt.Errorf("listing project flavors for region %s: %w", region, err)
+		}
+		return flavors, nil
+	})
+}
+
+// GetInstanceQuota returns the instance quotas of the project in a region
+func (c *OVHClient) GetInstanceQuota(ctx context.Context, region string) (*InstanceQuota, error) {
+	path := fmt.Sprintf("/cloud/project/%s/quota", c.serviceName)
+	quotas, err := retryableAPICall(ctx, c, callListQuotas, func() ([]Quota, error) {
+		var quotas []Quota
+		if err := c.client.GetWithContext(ctx, path, &quotas); err != nil {
+			return nil, fmt.Errorf("listing quotas: %w", err)
+		}
+		return quotas, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, quota := range quotas {
+		if strings.EqualFold(quota.Region, region) && quota.Instance != nil {
+			return quota.Instance, nil
+		}
+	}
+	return nil, fmt.Errorf("no instance quota for region %s", region)
+}
+
+// ListInstances returns the instances of the project in a region
+func (c *OVHClient) ListInstances(ctx context.Context, region string) ([]Instance, error) {
+	path := fmt.Sprintf("/cloud/project/%s/instance?region=%s", c.serviceName, region)
+	return retryableAPICall(ctx, c, callListInstances, func() ([]Instance, error) {
+		var instances []Instance
+		if err := c.client.GetWithContext(ctx, path, &instances); err != nil {
+			return nil, fmt.Errorf("listing instances for region %s: %w", region, err)
+		}
+		return instances, nil
+	})
+}
+
 // GetCluster returns the MKS cluster information including the region
 func (c *OVHClient) GetCluster(ctx context.Context) (*KubeCluster, error) {
 	path := c.basePath()
-	return retryableAPICall(ctx, c.retryConfig, "GetCluster", func() (*KubeCluster, error) {
+	return retryableAPICall(ctx, c, callGetCluster, func() (*KubeCluster, error) {
 		var cluster KubeCluster
 		if err := c.client.GetWithContext(ctx, path, &cluster); err != nil {
 			return nil, fmt.Errorf("getting cluster info: %w", err)
@@ -338,7 +618,7 @@ func (c *OVHClient) AutoDetectRegion(ctx context.Context) (string, error) {
 // API: DELETE /cloud/project/{serviceName}/kube/{kubeId}/node/{nodeId}
 func (c *OVHClient) DeleteNode(ctx context.Context, nodeID string) error {
 	path := fmt.Sprintf("%s/node/%s", c.basePath(), nodeID)
-	return retryableVoidCall(ctx, c.retryConfig, "DeleteNode", func() error {
+	return retryableVoidCall(ctx, c, callDeleteNode, func() error {
 		if err := c.client.DeleteWithContext(ctx, path, nil); err != nil {
 			return fmt.Errorf("deleting node %s: %w", nodeID, err)
 		}
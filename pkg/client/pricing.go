@@ -24,6 +24,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 // PricingCatalog represents the OVH public cloud pricing catalog
@@ -77,33 +79,123 @@ type PricingClient struct {
 	mu           sync.RWMutex
 	catalog      *PricingCatalog
 	lastRefresh  time.Time
+	lastAttempt  time.Time
 	cacheTTL     time.Duration
 	flavorPrices map[string]float64 // flavor name -> hourly price in EUR
+
+	// Validators of the cached catalog, sent as conditional request headers
+	etag         string
+	lastModified string
+
+	// refreshMu serializes catalog downloads so readers never wait on them
+	refreshMu sync.Mutex
+
+	monthlyPrices map[string]float64 // flavor name -> monthly price in EUR
+	pricedFlavors int                // number of distinct flavors priced by the catalog
+
+	// Flavors served by estimation since the last refresh
+	estimatedMu      sync.Mutex
+	estimatedFlavors map[string]struct{}
+
+	createdAt time.Time
+	observer  PricingObserver
 }
 
+// PricingObserver receives instrumentation events from the pricing client
+// It lets callers record metrics without pkg/client depending on a metrics package
+type PricingObserver interface {
+	// ObservePricingCacheHit is called when a price is served from the catalog
+	ObservePricingCacheHit()
+	// ObservePricingCacheMiss is called when a price falls back to estimation
+	ObservePricingCacheMiss()
+	// ObservePricingCacheRefresh is called after the catalog was successfully refreshed
+	ObservePricingCacheRefresh()
+	// ObservePricingRefreshFailure is called when the catalog could not be refreshed
+	ObservePricingRefreshFailure()
+}
+
+// noopPricingObserver discards all instrumentation events
+type noopPricingObserver struct{}
+
+func (noopPricingObserver) ObservePricingCacheHit()       {}
+func (noopPricingObserver) ObservePricingCacheMiss()      {}
+func (noopPricingObserver) ObservePricingCacheRefresh()   {}
+func (noopPricingObserver) ObservePricingRefreshFailure() {}
+
+const (
+	// HoursPerMonth is the number of hours OVHcloud bills in a month (365 * 24 / 12)
+	HoursPerMonth = 730
+
+	// DefaultPricingRefreshInterval is how often the pricing catalog is revalidated
+	DefaultPricingRefreshInterval = 6 * time.Hour
+
+	// DefaultPricingLoadTimeout bounds the catalog load blocking startup
+	DefaultPricingLoadTimeout = 30 * time.Second
+
+	// pricingRefreshJitter spreads refreshes of several replicas over 10% of the interval
+	pricingRefreshJitter = 0.1
+	// pricingRetryInterval is the minimum delay between two attempts after a failed refresh
+	pricingRetryInterval = time.Minute
+)
+
 // NewPricingClient creates a new pricing client
 func NewPricingClient(subsidiary string) *PricingClient {
 	if subsidiary == "" {
 		subsidiary = "FR"
 	}
 	return &PricingClient{
-		httpClient:   &http.Client{Timeout: 30 * time.Second},
-		baseURL:      "https://api.ovh.com/1.0/order/catalog/public/cloud",
-		subsidiary:   subsidiary,
-		cacheTTL:     6 * time.Hour, // Refresh prices every 6 hours
-		flavorPrices: make(map[string]float64),
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		baseURL:       "https://api.ovh.com/1.0/order/catalog/public/cloud",
+		subsidiary:    subsidiary,
+		cacheTTL:      DefaultPricingRefreshInterval,
+		flavorPrices:  make(map[string]float64),
+		monthlyPrices: make(map[string]float64),
+
+		estimatedFlavors: make(map[string]struct{}),
+		createdAt:        time.Now(),
+		observer:         noopPricingObserver{},
 	}
 }
 
+// WithObserver sets the observer notified of cache hits, misses and refreshes
+func (p *PricingClient) WithObserver(observer PricingObserver) *PricingClient {
+	p.observer = observer
+	return p
+}
+
+// WithRefreshInterval sets how often the catalog is revalidated
+func (p *PricingClient) WithRefreshInterval(interval time.Duration) *PricingClient {
+	p.cacheTTL = interval
+	return p
+}
+
+// WithBaseURL sets the URL the catalog is downloaded from
+func (p *PricingClient) WithBaseURL(baseURL string) *PricingClient {
+	p.baseURL = baseURL
+	return p
+}
+
 // GetFlavorPrice returns the hourly price for a flavor in EUR
 // Tries multiple lookup strategies: exact match, region-prefixed, catalog patterns
 func (p *PricingClient) GetFlavorPrice(ctx context.Context, flavorName string, region string) (float64, error) {
-	// Refresh cache if needed
-	if err := p.refreshCacheIfNeeded(ctx); err != nil {
-		// On error, return estimated price
-		return p.estimatePrice(flavorName), nil
+	// Prices are served from the last good catalog, or by estimation below when none was fetched yet
+	p.revalidate(ctx)
+
+	if price, ok := p.lookupPrice(flavorName, region); ok {
+		p.observer.ObservePricingCacheHit()
+		return price, nil
 	}
 
+	// Fall back to estimation
+	p.observer.ObservePricingCacheMiss()
+	p.estimatedMu.Lock()
+	p.estimatedFlavors[flavorName] = struct{}{}
+	p.estimatedMu.Unlock()
+	return p.estimatePrice(flavorName), nil
+}
+
+// lookupPrice looks a flavor up in the cached catalog prices
+func (p *PricingClient) lookupPrice(flavorName string, region string) (float64, bool) {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
@@ -111,89 +203,210 @@ func (p *PricingClient) GetFlavorPrice(ctx context.Context, flavorName string, r
 
 	// Strategy 1: Exact match by flavor name
 	if price, ok := p.flavorPrices[flavorName]; ok {
-		return price, nil
+		return price, true
 	}
 
 	// Strategy 2: Region prefix (e.g., "gra7.b3-8")
 	regionKey := fmt.Sprintf("%s.%s", regionLower, flavorName)
 	if price, ok := p.flavorPrices[regionKey]; ok {
-		return price, nil
+		return price, true
 	}
 
 	// Strategy 3: Instance catalog format (e.g., "instance-b3-8.gra7.hour.consumption")
 	catalogKey := fmt.Sprintf("instance-%s.%s.hour.consumption", flavorName, regionLower)
 	if price, ok := p.flavorPrices[catalogKey]; ok {
-		return price, nil
+		return price, true
 	}
 
 	// Strategy 4: Try without region-specific suffix (some flavors have global pricing)
 	// e.g., "instance-b3-8.hour.consumption"
 	globalKey := fmt.Sprintf("instance-%s.hour.consumption", flavorName)
 	if price, ok := p.flavorPrices[globalKey]; ok {
-		return price, nil
+		return price, true
 	}
 
-	// Fall back to estimation
-	return p.estimatePrice(flavorName), nil
+	return 0, false
 }
 
-// refreshCacheIfNeeded refreshes the pricing cache if it's stale
-func (p *PricingClient) refreshCacheIfNeeded(ctx context.Context) error {
+// CatalogPrice returns the hourly price of a flavor in EUR from the catalog
+// Unlike GetFlavorPrice it does not fall back to estimation: false is returned until the catalog prices the flavor
+func (p *PricingClient) CatalogPrice(ctx context.Context, flavorName string, region string) (float64, bool) {
+	p.revalidate(ctx)
+	return p.lookupPrice(flavorName, region)
+}
+
+// GetFlavorMonthlyPrice returns the monthly price for a flavor in EUR
+// Returns false if the catalog has no monthly billing plan for the flavor
+func (p *PricingClient) GetFlavorMonthlyPrice(ctx context.Context, flavorName string) (float64, bool) {
+	p.revalidate(ctx)
+
 	p.mu.RLock()
-	needsRefresh := p.catalog == nil || time.Since(p.lastRefresh) > p.cacheTTL
+	defer p.mu.RUnlock()
+	price, ok := p.monthlyPrices[flavorName]
+	return price, ok
+}
+
+// Start revalidates the catalog every refresh interval until the context is cancelled
+// Intervals are jittered so that replicas do not download the catalog at the same time
+func (p *PricingClient) Start(ctx context.Context) error {
+	for {
+		delay := wait.Jitter(p.cacheTTL, pricingRefreshJitter)
+		if err := p.Refresh(ctx); err != nil {
+			delay = wait.Jitter(pricingRetryInterval, pricingRefreshJitter)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns false as every replica prices instance types
+func (p *PricingClient) NeedLeaderElection() bool {
+	return false
+}
+
+// revalidate refreshes a missing or stale catalog in the background, so readers never block on a download
+// The first catalog is loaded at startup or by Start; until one is loaded, readers are served estimated prices,
+// and a stale catalog keeps being served while it is revalidated
+func (p *PricingClient) revalidate(ctx context.Context) {
+	p.mu.RLock()
+	loaded := p.catalog != nil
+	stale := time.Since(p.lastRefresh) > time.Duration(float64(p.cacheTTL)*(1+pricingRefreshJitter))
+	backoff := time.Since(p.lastAttempt) < pricingRetryInterval
 	p.mu.RUnlock()
 
-	if !needsRefresh {
-		return nil
+	switch {
+	case backoff:
+		return
+	case !loaded, stale:
+		if !p.refreshMu.TryLock() {
+			return // a refresh is already in flight
+		}
+		go func() {
+			defer p.refreshMu.Unlock()
+			_ = p.refreshLocked(context.WithoutCancel(ctx))
+		}()
 	}
+}
 
-	return p.refreshCache(ctx)
+// Refresh revalidates the catalog, downloading it only if it changed since the last refresh
+// Readers keep being served from the previous catalog until the new one is parsed
+func (p *PricingClient) Refresh(ctx context.Context) error {
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+	return p.refreshLocked(ctx)
 }
 
-// refreshCache fetches fresh pricing data from OVH API
-func (p *PricingClient) refreshCache(ctx context.Context) error {
+// refreshLocked fetches the catalog and swaps the cached prices; callers must hold refreshMu
+func (p *PricingClient) refreshLocked(ctx context.Context) error {
+	p.mu.Lock()
+	p.lastAttempt = time.Now()
+	etag, lastModified := p.etag, p.lastModified
+	if p.catalog == nil {
+		etag, lastModified = "", ""
+	}
+	p.mu.Unlock()
+
+	result, err := p.fetchCatalog(ctx, etag, lastModified)
+	if err != nil {
+		p.observer.ObservePricingRefreshFailure()
+		return err
+	}
+
 	p.mu.Lock()
-	defer p.mu.Unlock()
+	p.lastRefresh = time.Now()
+	if result.catalog != nil {
+		p.catalog = result.catalog
+		p.etag = result.etag
+		p.lastModified = result.lastModified
+		p.flavorPrices = result.prices.hourly
+		p.monthlyPrices = result.prices.monthly
+		p.pricedFlavors = result.prices.flavors
+	}
+	p.mu.Unlock()
+	p.observer.ObservePricingCacheRefresh()
 
-	// Double-check after acquiring write lock
-	if p.catalog != nil && time.Since(p.lastRefresh) <= p.cacheTTL {
-		return nil
+	if result.catalog != nil {
+		// Flavors estimated against the previous catalog may be priced now
+		p.estimatedMu.Lock()
+		p.estimatedFlavors = make(map[string]struct{})
+		p.estimatedMu.Unlock()
 	}
 
+	return nil
+}
+
+// catalogResult is a downloaded catalog with its prices and validators
+// catalog is nil when the server reported the cached catalog as not modified
+type catalogResult struct {
+	catalog      *PricingCatalog
+	prices       flavorPrices
+	etag         string
+	lastModified string
+}
+
+// fetchCatalog downloads the catalog unless it matches the given validators
+func (p *PricingClient) fetchCatalog(ctx context.Context, etag, lastModified string) (catalogResult, error) {
 	url := fmt.Sprintf("%s?ovhSubsidiary=%s", p.baseURL, p.subsidiary)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
+		return catalogResult{}, fmt.Errorf("creating request: %w", err)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
 	}
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("fetching pricing catalog: %w", err)
+		return catalogResult{}, fmt.Errorf("fetching pricing catalog: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return catalogResult{}, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("pricing API returned status %d", resp.StatusCode)
+		return catalogResult{}, fmt.Errorf("pricing API returned status %d", resp.StatusCode)
 	}
 
 	var catalog PricingCatalog
 	if err := json.NewDecoder(resp.Body).Decode(&catalog); err != nil {
-		return fmt.Errorf("decoding pricing catalog: %w", err)
+		return catalogResult{}, fmt.Errorf("decoding pricing catalog: %w", err)
 	}
 
-	p.catalog = &catalog
-	p.lastRefresh = time.Now()
-	p.extractFlavorPrices()
+	return catalogResult{
+		catalog:      &catalog,
+		prices:       extractFlavorPrices(&catalog),
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
 
-	return nil
+// flavorPrices are the prices extracted from a catalog
+type flavorPrices struct {
+	hourly  map[string]float64 // flavor name or plan code -> hourly price in EUR
+	monthly map[string]float64 // flavor name -> monthly price in EUR
+	flavors int                // number of distinct flavors with an hourly price
 }
 
-// extractFlavorPrices parses the catalog and extracts hourly prices for flavors
-func (p *PricingClient) extractFlavorPrices() {
-	p.flavorPrices = make(map[string]float64)
+// extractFlavorPrices parses the catalog and extracts hourly and monthly prices for flavors
+func extractFlavorPrices(catalog *PricingCatalog) flavorPrices {
+	prices := flavorPrices{
+		hourly:  make(map[string]float64),
+		monthly: make(map[string]float64),
+	}
+	priced := make(map[string]struct{})
 
 	// Extract from addons (where instance flavors are typically listed)
-	for _, addon := range p.catalog.Addons {
+	for _, addon := range catalog.Addons {
 		// Look for instance-related plan codes
 		// OVH format: "instance-{flavor}.{region}.hour.consumption" or similar patterns
 		planCode := strings.ToLower(addon.PlanCode)
@@ -209,13 +422,25 @@ func (p *PricingClient) extractFlavorPrices() {
 				pricing.Interval == 1
 
 			hasConsumption := false
+			hasRenew := false
 			for _, cap := range pricing.Capacities {
-				if cap == "consumption" {
+				switch cap {
+				case "consumption":
 					hasConsumption = true
-					break
+				case "renew":
+					hasRenew = true
 				}
 			}
 
+			// Monthly billing plans (e.g., "instance-b2-7.monthly.postpaid") are renewed every month
+			isMonthly := strings.Contains(planCode, ".monthly") || strings.Contains(pricing.Duration, "P1M")
+			if isMonthly && hasRenew && pricing.Price > 0 {
+				if flavorName := extractFlavorFromPlanCode(planCode); flavorName != "" {
+					prices.monthly[flavorName] = float64(pricing.Price) / 100000000.0
+				}
+				continue
+			}
+
 			if isHourly && hasConsumption && pricing.Price > 0 {
 				// Extract flavor name from plan code
 				// Examples: "instance-b3-8.gra7.hour.consumption" -> "b3-8"
@@ -223,14 +448,17 @@ func (p *PricingClient) extractFlavorPrices() {
 				if flavorName != "" {
 					// Price is in micro-units, convert to EUR
 					priceEUR := float64(pricing.Price) / 100000000.0
-					p.flavorPrices[flavorName] = priceEUR
+					prices.hourly[flavorName] = priceEUR
 
 					// Also store with full plan code for region-specific lookup
-					p.flavorPrices[planCode] = priceEUR
+					prices.hourly[planCode] = priceEUR
+					priced[flavorName] = struct{}{}
 				}
 			}
 		}
 	}
+	prices.flavors = len(priced)
+	return prices
 }
 
 // extractFlavorFromPlanCode extracts the flavor name from an OVH plan code
@@ -309,14 +537,40 @@ func (p *PricingClient) estimatePrice(flavorName string) float64 {
 	return 0.10
 }
 
-// ForceRefresh forces a refresh of the pricing cache
+// ForceRefresh forces a full download of the pricing catalog, ignoring its validators
+// The previous catalog keeps being served until the download completes
 func (p *PricingClient) ForceRefresh(ctx context.Context) error {
 	p.mu.Lock()
-	p.catalog = nil
-	p.lastRefresh = time.Time{}
+	p.etag = ""
+	p.lastModified = ""
 	p.mu.Unlock()
 
-	return p.refreshCache(ctx)
+	return p.Refresh(ctx)
+}
+
+// CatalogAge returns the time since the catalog was last refreshed
+// If the catalog was never fetched, it returns the time since the client was created
+func (p *PricingClient) CatalogAge() time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.lastRefresh.IsZero() {
+		return time.Since(p.createdAt)
+	}
+	return time.Since(p.lastRefresh)
+}
+
+// PricedFlavorCount returns the number of flavors priced by the cached catalog
+func (p *PricingClient) PricedFlavorCount() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.pricedFlavors
+}
+
+// EstimatedFlavorCount returns the number of flavors served by estimation since the last refresh
+func (p *PricingClient) EstimatedFlavorCount() int {
+	p.estimatedMu.Lock()
+	defer p.estimatedMu.Unlock()
+	return len(p.estimatedFlavors)
 }
 
 // GetCachedPrices returns all cached flavor prices (for debugging)
@@ -0,0 +1,108 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Endpoint classes rate limited independently
+const (
+	EndpointClassRead  = "read"
+	EndpointClassWrite = "write"
+)
+
+// RateLimitConfig defines the client-side rate limits applied to OVH API calls
+// Reads (GET) and writes (POST, PUT, DELETE) have separate token buckets so that
+// inventory polling cannot starve pool scaling, and MaxInFlight bounds concurrent requests
+type RateLimitConfig struct {
+	ReadQPS     float64
+	ReadBurst   int
+	WriteQPS    float64
+	WriteBurst  int
+	MaxInFlight int
+}
+
+// DefaultRateLimitConfig stays well below the OVHcloud API quotas of a single application
+var DefaultRateLimitConfig = RateLimitConfig{
+	ReadQPS:     10,
+	ReadBurst:   20,
+	WriteQPS:    2,
+	WriteBurst:  5,
+	MaxInFlight: 10,
+}
+
+// rateLimiter applies the token buckets and in-flight limit of a RateLimitConfig
+type rateLimiter struct {
+	read     *rate.Limiter
+	write    *rate.Limiter
+	inFlight chan struct{}
+}
+
+// newRateLimiter creates a rate limiter; non-positive QPS or MaxInFlight disable the matching limit
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	limiter := &rateLimiter{
+		read:  rate.NewLimiter(rate.Inf, 0),
+		write: rate.NewLimiter(rate.Inf, 0),
+	}
+	if config.ReadQPS > 0 {
+		limiter.read = rate.NewLimiter(rate.Limit(config.ReadQPS), max(config.ReadBurst, 1))
+	}
+	if config.WriteQPS > 0 {
+		limiter.write = rate.NewLimiter(rate.Limit(config.WriteQPS), max(config.WriteBurst, 1))
+	}
+	if config.MaxInFlight > 0 {
+		limiter.inFlight = make(chan struct{}, config.MaxInFlight)
+	}
+	return limiter
+}
+
+// endpointClass returns the rate limiting class of an HTTP method
+func endpointClass(method string) string {
+	if method == http.MethodGet {
+		return EndpointClassRead
+	}
+	return EndpointClassWrite
+}
+
+// acquire waits for a token of the call's class and an in-flight slot
+// It returns the time spent waiting and a function releasing the slot
+func (l *rateLimiter) acquire(ctx context.Context, call apiCall) (func(), time.Duration, error) {
+	start := time.Now()
+
+	limiter := l.read
+	if endpointClass(call.Method) == EndpointClassWrite {
+		limiter = l.write
+	}
+	if err := limiter.Wait(ctx); err != nil {
+		return nil, time.Since(start), err
+	}
+
+	if l.inFlight == nil {
+		return func() {}, time.Since(start), nil
+	}
+	select {
+	case l.inFlight <- struct{}{}:
+		return func() { <-l.inFlight }, time.Since(start), nil
+	case <-ctx.Done():
+		return nil, time.Since(start), ctx.Err()
+	}
+}
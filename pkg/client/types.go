@@ -22,21 +22,33 @@ import (
 
 // NodePool represents an OVH MKS Node Pool
 type NodePool struct {
-	ID               string            `json:"id"`
-	Name             string            `json:"name"`
-	FlavorName       string            `json:"flavor"`
-	DesiredNodes     int               `json:"desiredNodes"`
-	CurrentNodes     int               `json:"currentNodes"`
-	MinNodes         int               `json:"minNodes"`
-	MaxNodes         int               `json:"maxNodes"`
-	Autoscale        bool              `json:"autoscale"`
-	MonthlyBilled    bool              `json:"monthlyBilled"`
-	AntiAffinity     bool              `json:"antiAffinity"`
-	Status           string            `json:"status"`
-	AvailabilityZone string            `json:"availabilityZone,omitempty"`
-	Template         *NodePoolTemplate `json:"template,omitempty"`
-	CreatedAt        string            `json:"createdAt"`
-	UpdatedAt        string            `json:"updatedAt"`
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	FlavorName        string            `json:"flavor"`
+	DesiredNodes      int               `json:"desiredNodes"`
+	CurrentNodes      int               `json:"currentNodes"`
+	MinNodes          int               `json:"minNodes"`
+	MaxNodes          int               `json:"maxNodes"`
+	Autoscale         bool              `json:"autoscale"`
+	MonthlyBilled     bool              `json:"monthlyBilled"`
+	AntiAffinity      bool              `json:"antiAffinity"`
+	Status            string            `json:"status"`
+	AvailabilityZone  string            `json:"availabilityZone,omitempty"`
+	AvailabilityZones []string          `json:"availabilityZones,omitempty"` // set in multi-AZ regions
+	Template          *NodePoolTemplate `json:"template,omitempty"`
+	CreatedAt         string            `json:"createdAt"`
+	UpdatedAt         string            `json:"updatedAt"`
+}
+
+// Zone returns the availability zone of the pool, or an empty string if it has none
+func (p *NodePool) Zone() string {
+	if p.AvailabilityZone != "" {
+		return p.AvailabilityZone
+	}
+	if len(p.AvailabilityZones) > 0 {
+		return p.AvailabilityZones[0]
+	}
+	return ""
 }
 
 // NodePoolTemplate defines the template for nodes in a pool
@@ -121,6 +133,52 @@ type KubeFlavorCapability struct {
 	State    string `json:"state"` // "available" or other
 }
 
+// ProjectFlavor represents a flavor from the Public Cloud project flavor API
+// The capabilities API does not report disk sizes, so this is joined onto capability flavors
+type ProjectFlavor struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Region    string `json:"region"`
+	VCPUs     int    `json:"vcpus"`
+	Disk      int    `json:"disk"` // root disk in GiB
+	OSType    string `json:"osType"`
+	Type      string `json:"type"`
+	Available bool   `json:"available"`
+}
+
+// Instance represents a Public Cloud instance of the project
+// MKS nodes are instances; Node.InstanceID references Instance.ID
+type Instance struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Region string `json:"region"`
+	Status string `json:"status"` // ACTIVE, MIGRATING, REBOOT, HARD_REBOOT, RESCUE, ...
+}
+
+// Quota represents the Public Cloud project quotas of a region
+type Quota struct {
+	Region   string         `json:"region"`
+	Instance *InstanceQuota `json:"instance"`
+}
+
+// InstanceQuota represents the instance quotas of a region
+type InstanceQuota struct {
+	MaxCores      int `json:"maxCores"`
+	MaxInstances  int `json:"maxInstances"`
+	MaxRAM        int `json:"maxRam"` // in MiB
+	UsedCores     int `json:"usedCores"`
+	UsedInstances int `json:"usedInstances"`
+	UsedRAM       int `json:"usedRAM"` // in MiB
+}
+
+// Fits returns true if one more instance with the given vCPUs and RAM (in MiB) fits in the quota
+// Limits that are not reported (zero) are not enforced
+func (q *InstanceQuota) Fits(vcpus, ramMiB int) bool {
+	return (q.MaxInstances <= 0 || q.UsedInstances+1 <= q.MaxInstances) &&
+		(q.MaxCores <= 0 || q.UsedCores+vcpus <= q.MaxCores) &&
+		(q.MaxRAM <= 0 || q.UsedRAM+ramMiB <= q.MaxRAM)
+}
+
 // KubeCluster represents an OVH MKS cluster
 type KubeCluster struct {
 	ID                          string                `json:"id"`
@@ -0,0 +1,284 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+)
+
+// AllocatableConfigMapName is the ConfigMap persisting observed allocatable resources per flavor
+const AllocatableConfigMapName = "karpenter-ovhcloud-allocatable"
+
+// allocatableLoadRetryInterval is how often loading the persisted observations is retried at startup
+const allocatableLoadRetryInterval = 10 * time.Second
+
+// allocatableReloadInterval is how often every replica re-reads the persisted observations
+// Only the leader records Nodes, the other replicas learn its observations from the ConfigMap
+const allocatableReloadInterval = time.Minute
+
+// observedResources are the resources learned from registered Nodes
+// Other resources (pods, GPUs) keep their modelled values
+var observedResources = []corev1.ResourceName{
+	corev1.ResourceCPU,
+	corev1.ResourceMemory,
+	corev1.ResourceEphemeralStorage,
+}
+
+// AllocatableCache records the allocatable resources observed on registered MKS Nodes per flavor
+// It is backed by a ConfigMap so observations survive controller restarts
+type AllocatableCache struct {
+	kubeClient client.Client
+	namespace  string
+
+	mu       sync.RWMutex
+	loaded   bool
+	observed map[string]corev1.ResourceList // flavor name -> smallest observed allocatable
+}
+
+// NewAllocatableCache creates a new allocatable cache persisted in the given namespace
+func NewAllocatableCache(kubeClient client.Client, namespace string) *AllocatableCache {
+	return &AllocatableCache{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		observed:   make(map[string]corev1.ResourceList),
+	}
+}
+
+// Start loads the persisted observations, so that Get serves them before any Node is recorded,
+// then re-reads them every reload interval to pick up what the leader recorded
+// Loading is retried until it succeeds or the context is cancelled
+func (a *AllocatableCache) Start(ctx context.Context) error {
+	err := wait.PollUntilContextCancel(ctx, allocatableLoadRetryInterval, true, func(ctx context.Context) (bool, error) {
+		if err := a.load(ctx); err != nil {
+			log.FromContext(ctx).V(1).Info("Failed to load observed allocatable resources, retrying", "error", err)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			return err
+		}
+		return nil
+	}
+
+	ticker := time.NewTicker(allocatableReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := a.reload(ctx); err != nil {
+				log.FromContext(ctx).V(1).Info("Failed to reload observed allocatable resources", "error", err)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false as every replica builds instance types
+func (a *AllocatableCache) NeedLeaderElection() bool {
+	return false
+}
+
+// Get returns the observed allocatable resources for a flavor
+func (a *AllocatableCache) Get(flavor string) (corev1.ResourceList, bool) {
+	if a == nil {
+		return nil, false
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	allocatable, ok := a.observed[flavor]
+	return allocatable, ok
+}
+
+// Len returns the number of flavors with observed allocatable resources
+func (a *AllocatableCache) Len() int {
+	if a == nil {
+		return 0
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.observed)
+}
+
+// Record merges the allocatable resources of a registered Node into the cache
+// The smallest value per resource is kept, so a flavor is never over-packed
+// because one of its nodes happened to reserve less than the others
+func (a *AllocatableCache) Record(ctx context.Context, flavor string, allocatable corev1.ResourceList) error {
+	if err := a.load(ctx); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	current := a.observed[flavor]
+	merged := minResources(current, allocatable)
+
+	if equality.Semantic.DeepEqual(current, merged) {
+		return nil
+	}
+	a.observed[flavor] = merged
+	return a.persist(ctx)
+}
+
+// load reads the persisted observations once
+func (a *AllocatableCache) load(ctx context.Context) error {
+	a.mu.RLock()
+	loaded := a.loaded
+	a.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+	if err := a.reload(ctx); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.loaded = true
+	a.mu.Unlock()
+	return nil
+}
+
+// reload merges the persisted observations into the cache
+// The smallest value per resource is kept, as when recording a Node
+func (a *AllocatableCache) reload(ctx context.Context) error {
+	cm := &corev1.ConfigMap{}
+	err := a.kubeClient.Get(ctx, types.NamespacedName{Namespace: a.namespace, Name: AllocatableConfigMapName}, cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("getting allocatable configmap: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for flavor, raw := range cm.Data {
+		var values map[corev1.ResourceName]string
+		if err := json.Unmarshal([]byte(raw), &values); err != nil {
+			continue
+		}
+		allocatable := corev1.ResourceList{}
+		for name, value := range values {
+			if quantity, err := resource.ParseQuantity(value); err == nil {
+				allocatable[name] = quantity
+			}
+		}
+		a.observed[flavor] = minResources(a.observed[flavor], allocatable)
+	}
+	return nil
+}
+
+// minResources returns the smallest value per observed resource of two allocatable lists
+func minResources(current, observed corev1.ResourceList) corev1.ResourceList {
+	merged := corev1.ResourceList{}
+	for _, name := range observedResources {
+		quantity, ok := observed[name]
+		existing, exists := current[name]
+		switch {
+		case !ok && !exists:
+			continue
+		case !ok, exists && existing.Cmp(quantity) < 0:
+			quantity = existing
+		}
+		merged[name] = quantity.DeepCopy()
+	}
+	return merged
+}
+
+// persist writes all observations to the ConfigMap; callers must hold the write lock
+func (a *AllocatableCache) persist(ctx context.Context) error {
+	data := make(map[string]string, len(a.observed))
+	for flavor, allocatable := range a.observed {
+		values := make(map[corev1.ResourceName]string, len(allocatable))
+		for name, quantity := range allocatable {
+			values[name] = quantity.String()
+		}
+		raw, err := json.Marshal(values)
+		if err != nil {
+			return fmt.Errorf("encoding allocatable for %s: %w", flavor, err)
+		}
+		data[flavor] = string(raw)
+	}
+
+	cm := &corev1.ConfigMap{}
+	err := a.kubeClient.Get(ctx, types.NamespacedName{Namespace: a.namespace, Name: AllocatableConfigMapName}, cm)
+	if errors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: a.namespace,
+				Name:      AllocatableConfigMapName,
+			},
+			Data: data,
+		}
+		if err := a.kubeClient.Create(ctx, cm); err != nil {
+			return fmt.Errorf("creating allocatable configmap: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting allocatable configmap: %w", err)
+	}
+
+	cm.Data = data
+	if err := a.kubeClient.Update(ctx, cm); err != nil {
+		return fmt.Errorf("updating allocatable configmap: %w", err)
+	}
+	return nil
+}
+
+// observedOverhead expresses observed allocatable resources as an overhead on top of the capacity,
+// so that InstanceType.Allocatable() returns exactly what the registered Nodes reported
+func observedOverhead(capacity, observed corev1.ResourceList, modelled *cloudprovider.InstanceTypeOverhead) *cloudprovider.InstanceTypeOverhead {
+	overhead := &cloudprovider.InstanceTypeOverhead{
+		KubeReserved:      corev1.ResourceList{},
+		SystemReserved:    corev1.ResourceList{},
+		EvictionThreshold: corev1.ResourceList{},
+	}
+	// Keep the modelled overhead for resources that were not observed
+	for name, quantity := range modelled.Total() {
+		if _, ok := observed[name]; !ok {
+			overhead.KubeReserved[name] = quantity
+		}
+	}
+	for name, allocatable := range observed {
+		total, ok := capacity[name]
+		if !ok {
+			continue
+		}
+		reserved := total.DeepCopy()
+		reserved.Sub(allocatable)
+		if reserved.Sign() < 0 {
+			reserved = resource.Quantity{}
+		}
+		overhead.KubeReserved[name] = reserved
+	}
+	return overhead
+}
@@ -0,0 +1,107 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAllocatableCacheReloadsLeaderObservations(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := fakeclient.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	leader := NewAllocatableCache(kubeClient, "karpenter")
+	replica := NewAllocatableCache(kubeClient, "karpenter")
+	if err := replica.load(ctx); err != nil {
+		t.Fatalf("loading: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		// recorded is the allocatable of a Node recorded by the leader
+		recorded corev1.ResourceList
+		// local is what the replica observed before the reload
+		local corev1.ResourceList
+		want  corev1.ResourceList
+	}{
+		{
+			name:     "observation of the leader",
+			recorded: testCapacity("1900m", "6Gi", "45Gi"),
+			want:     testCapacity("1900m", "6Gi", "45Gi"),
+		},
+		{
+			name:     "smaller observation of the leader",
+			recorded: testCapacity("1800m", "6Gi", "45Gi"),
+			want:     testCapacity("1800m", "6Gi", "45Gi"),
+		},
+		{
+			name:     "smaller local values are kept",
+			recorded: testCapacity("1800m", "6Gi", "45Gi"),
+			local:    testCapacity("1800m", "5Gi", "45Gi"),
+			want:     testCapacity("1800m", "5Gi", "45Gi"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := leader.Record(ctx, "b3-8", tt.recorded); err != nil {
+				t.Fatalf("recording: %v", err)
+			}
+			if tt.local != nil {
+				replica.mu.Lock()
+				replica.observed["b3-8"] = tt.local
+				replica.mu.Unlock()
+			}
+			if err := replica.reload(ctx); err != nil {
+				t.Fatalf("reloading: %v", err)
+			}
+			got, ok := replica.Get("b3-8")
+			if !ok {
+				t.Fatalf("replica did not pick up the observation of the leader")
+			}
+			for name, want := range tt.want {
+				if actual := got[name]; actual.Cmp(want) != 0 {
+					t.Errorf("%s = %s, want %s", name, actual.String(), want.String())
+				}
+			}
+		})
+	}
+}
+
+func TestMinResources(t *testing.T) {
+	got := minResources(
+		corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("4Gi")},
+		corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("3Gi"), corev1.ResourceEphemeralStorage: resource.MustParse("40Gi"), corev1.ResourcePods: resource.MustParse("110")},
+	)
+	want := corev1.ResourceList{
+		corev1.ResourceCPU:              resource.MustParse("1"),
+		corev1.ResourceMemory:           resource.MustParse("3Gi"),
+		corev1.ResourceEphemeralStorage: resource.MustParse("40Gi"),
+	}
+	if len(got) != len(want) {
+		t.Errorf("minResources = %v, want %v", got, want)
+	}
+	for name, quantity := range want {
+		if actual := got[name]; actual.Cmp(quantity) != 0 {
+			t.Errorf("%s = %s, want %s", name, actual.String(), quantity.String())
+		}
+	}
+}
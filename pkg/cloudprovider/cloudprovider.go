@@ -20,6 +20,7 @@ import (
 	"context"
 	stderrors "errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -34,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis"
 	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis/v1alpha1"
 	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
@@ -48,39 +50,83 @@ type CloudProvider struct {
 	pricingClient *ovhclient.PricingClient
 	instanceTypes []*cloudprovider.InstanceType
 
+	// Allocatable resources observed on registered Nodes, preferred over the modelled overhead
+	allocatableCache *AllocatableCache
+
+	// Snapshot of the Karpenter-managed pools and nodes read by Get and List
+	inventory *Inventory
+
+	// Desired-count changes queued while MKS reconciles a pool
+	scaler *poolScaler
+
+	// Flavor and zone pairs that recently failed to launch
+	unavailableOfferings *UnavailableOfferings
+
 	// Mutex for pool operations
 	mu sync.RWMutex
 	// Cache of pool names to pool IDs
 	poolCache map[string]string
+	// Node IDs claimed by launches, by pool ID
+	claimedNodes map[string]map[string]bool
+	// Launches waiting for a node to claim, by pool ID
+	waitingLaunches map[string]int
+	// Launches abandoned before their node could be deleted, by pool ID
+	abandonedLaunches map[string][]abandonedLaunch
 }
 
 // NewCloudProvider creates a new OVHcloud CloudProvider
-func NewCloudProvider(ctx context.Context, kubeClient client.Client, ovhClient *ovhclient.OVHClient, instanceTypes []*cloudprovider.InstanceType) *CloudProvider {
+func NewCloudProvider(ctx context.Context, kubeClient client.Client, ovhClient *ovhclient.OVHClient, inventory *Inventory, instanceTypes []*cloudprovider.InstanceType) *CloudProvider {
 	return &CloudProvider{
 		kubeClient:    kubeClient,
 		ovhClient:     ovhClient,
 		pricingClient: ovhclient.NewPricingClient("FR"), // Default to FR subsidiary
 		instanceTypes: instanceTypes,
-		poolCache:     make(map[string]string),
+		inventory:     inventory,
+		scaler:        newPoolScaler(ovhClient),
+
+		unavailableOfferings: NewUnavailableOfferings(DefaultUnavailableOfferingsTTL),
+		poolCache:            make(map[string]string),
+		claimedNodes:         make(map[string]map[string]bool),
+		waitingLaunches:      make(map[string]int),
+		abandonedLaunches:    make(map[string][]abandonedLaunch),
 	}
 }
 
 // NewCloudProviderWithPricing creates a new OVHcloud CloudProvider with custom pricing client
-func NewCloudProviderWithPricing(ctx context.Context, kubeClient client.Client, ovhClient *ovhclient.OVHClient, pricingClient *ovhclient.PricingClient, instanceTypes []*cloudprovider.InstanceType) *CloudProvider {
+func NewCloudProviderWithPricing(ctx context.Context, kubeClient client.Client, ovhClient *ovhclient.OVHClient, pricingClient *ovhclient.PricingClient, inventory *Inventory, instanceTypes []*cloudprovider.InstanceType) *CloudProvider {
 	return &CloudProvider{
 		kubeClient:    kubeClient,
 		ovhClient:     ovhClient,
 		pricingClient: pricingClient,
 		instanceTypes: instanceTypes,
-		poolCache:     make(map[string]string),
+		inventory:     inventory,
+		scaler:        newPoolScaler(ovhClient),
+
+		unavailableOfferings: NewUnavailableOfferings(DefaultUnavailableOfferingsTTL),
+		poolCache:            make(map[string]string),
+		claimedNodes:         make(map[string]map[string]bool),
+		waitingLaunches:      make(map[string]int),
+		abandonedLaunches:    make(map[string][]abandonedLaunch),
 	}
 }
 
+// WithAllocatableCache sets the cache of allocatable resources observed on registered Nodes
+func (c *CloudProvider) WithAllocatableCache(cache *AllocatableCache) *CloudProvider {
+	c.allocatableCache = cache
+	return c
+}
+
 // Create launches a NodeClaim by creating or scaling up an OVH Node Pool
 func (c *CloudProvider) Create(ctx context.Context, nodeClaim *v1.NodeClaim) (*v1.NodeClaim, error) {
 	logger := log.FromContext(ctx)
 	startTime := time.Now()
 
+	// Fail fast while the OVH API is unavailable, the NodeClaim launch is retried with backoff
+	if c.ovhClient.CircuitState() == ovhclient.CircuitOpen {
+		RecordNodeProvisioning("unknown", "unknown", "api_unavailable")
+		return nil, fmt.Errorf("launching node: %w", ovhclient.ErrCircuitOpen)
+	}
+
 	// Resolve NodeClass
 	nodeClass, err := c.resolveNodeClass(ctx, nodeClaim)
 	if err != nil {
@@ -98,31 +144,42 @@ func (c *CloudProvider) Create(ctx context.Context, nodeClaim *v1.NodeClaim) (*v
 		return nil, cloudprovider.NewNodeClassNotReadyError(stderrors.New(readyCondition.Message))
 	}
 
-	// Determine flavor and zone from requirements
-	flavor, err := c.selectFlavor(nodeClaim)
-	if err != nil {
-		RecordNodeProvisioning("unknown", "unknown", "no_flavor")
-		return nil, fmt.Errorf("selecting flavor: %w", err)
+	// Try the cheapest viable offerings first, falling through to the next one on capacity errors
+	instanceTypes := c.instanceTypesForNodeClass(ctx, nodeClass)
+	candidates := c.launchCandidates(ctx, nodeClaim, instanceTypes)
+	if len(candidates) == 0 {
+		RecordNodeProvisioning("unknown", "unknown", "no_offering")
+		return nil, cloudprovider.NewInsufficientCapacityError(fmt.Errorf("no available offering matches the NodeClaim requirements"))
 	}
 
-	zone := c.selectZone(nodeClaim)
-	poolName := c.poolName(flavor, zone)
-
-	logger.Info("Creating node", "flavor", flavor, "zone", zone, "poolName", poolName)
-
-	// Get or create the pool with labels and taints from NodeClaim
-	// Also get the existing node IDs BEFORE scaling up, so we can identify the NEW node
-	pool, existingNodeIDs, err := c.getOrCreatePool(ctx, poolName, flavor, zone, nodeClass, nodeClaim)
-	if err != nil {
-		RecordNodeProvisioning(flavor, zone, "pool_error")
-		return nil, fmt.Errorf("getting/creating pool: %w", err)
+	var (
+		flavor, zone string
+		pool         *ovhclient.NodePool
+		node         *ovhclient.Node
+		launchErrs   []error
+	)
+	for _, candidate := range candidates {
+		flavor, zone = candidate.InstanceType.Name, candidate.Zone
+		pool, node, err = c.launch(ctx, nodeClaim, nodeClass, flavor, zone, candidate.Price)
+		if err == nil {
+			break
+		}
+		// A node that failed in MKS may take the whole launch timeout to detect, so rather than
+		// trying the next candidate the NodeClaim is given back to Karpenter, which reschedules it
+		// away from the offering now marked unavailable
+		if stderrors.Is(err, errNodeLaunchFailed) {
+			c.unavailableOfferings.MarkUnavailable(flavor, zone, "launch_failed")
+			return nil, cloudprovider.NewInsufficientCapacityError(fmt.Errorf("launching node: %w", err))
+		}
+		if !ovhclient.IsCapacityError(err) {
+			return nil, err
+		}
+		c.unavailableOfferings.MarkUnavailable(flavor, zone, "insufficient_capacity")
+		launchErrs = append(launchErrs, fmt.Errorf("%s in %s: %w", flavor, zone, err))
+		logger.Info("Offering out of capacity, trying next candidate", "flavor", flavor, "zone", zone, "error", err)
 	}
-
-	// Wait for a new node to appear (one that wasn't in existingNodeIDs)
-	node, err := c.waitForNewNode(ctx, pool.ID, existingNodeIDs)
 	if err != nil {
-		RecordNodeProvisioning(flavor, zone, "timeout")
-		return nil, fmt.Errorf("waiting for new node: %w", err)
+		return nil, cloudprovider.NewInsufficientCapacityError(fmt.Errorf("launching node: %w", stderrors.Join(launchErrs...)))
 	}
 
 	// Record successful provisioning metrics
@@ -133,7 +190,9 @@ func (c *CloudProvider) Create(ctx context.Context, nodeClaim *v1.NodeClaim) (*v
 	logger.Info("Node created", "nodeID", node.ID, "nodeName", node.Name, "poolID", pool.ID, "durationSeconds", duration)
 
 	// Build the response NodeClaim
-	instanceType, _ := c.getInstanceType(flavor)
+	instanceType, _ := lo.Find(instanceTypes, func(it *cloudprovider.InstanceType) bool {
+		return it.Name == flavor
+	})
 	created := nodeClaim.DeepCopy()
 	// Use OpenStack instance ID format to match what OVH MKS sets on nodes
 	created.Status.ProviderID = fmt.Sprintf("%s%s", ProviderPrefix, node.InstanceID)
@@ -159,85 +218,130 @@ func (c *CloudProvider) Create(ctx context.Context, nodeClaim *v1.NodeClaim) (*v
 	return created, nil
 }
 
-// Delete removes a NodeClaim by deleting the specific node from OVH
-// If the node is the last one in the pool, the entire pool is deleted
+// Delete removes the node backing a NodeClaim
+// Only that node is ever removed: it is deleted by ID, or together with its pool when it is the
+// pool's only node. The desired count is never lowered, which would let MKS pick the node to remove.
+// Karpenter calls Delete until it returns NotFound, which happens once MKS no longer lists the node.
 func (c *CloudProvider) Delete(ctx context.Context, nodeClaim *v1.NodeClaim) error {
 	logger := log.FromContext(ctx)
-	startTime := time.Now()
 
 	poolID := nodeClaim.Annotations[v1alpha1.AnnotationOVHPoolID]
 	nodeID := nodeClaim.Annotations[v1alpha1.AnnotationOVHNodeID]
+	instanceID := strings.TrimPrefix(nodeClaim.Status.ProviderID, ProviderPrefix)
 
+	// Resolve the pool and node from the instance ID when the annotations are missing
+	if (poolID == "" || nodeID == "") && instanceID != "" {
+		node, pool, err := c.inventory.FindInstance(ctx, instanceID)
+		if err != nil {
+			RecordNodeDeletion("lookup_error")
+			return fmt.Errorf("looking up instance %s: %w", instanceID, err)
+		}
+		if node != nil {
+			poolID, nodeID = pool.ID, node.ID
+		}
+	}
 	if poolID == "" {
 		RecordNodeDeletion("no_pool_id")
-		return cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("no pool ID annotation"))
+		return cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("no pool found for instance %q", instanceID))
 	}
 
-	// Get the current pool state
-	pool, err := c.ovhClient.GetNodePool(ctx, poolID)
-	if err != nil {
-		// Pool might already be deleted
+	nodes, err := c.ovhClient.ListPoolNodes(ctx, poolID)
+	if ovhclient.IsNotFound(err) {
+		// Pool already deleted
+		c.inventory.RemovePool(poolID)
+		c.releasePool(poolID)
 		RecordNodeDeletion("pool_not_found")
 		return cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("pool not found: %w", err))
 	}
+	if err != nil {
+		RecordNodeDeletion("pool_error")
+		return fmt.Errorf("listing pool nodes: %w", err)
+	}
 
-	logger.Info("Deleting node", "nodeID", nodeID, "poolID", poolID, "currentNodes", pool.CurrentNodes)
-
-	// If this is the last node in the pool, delete the entire pool
-	if pool.CurrentNodes <= 1 {
-		if err := c.ovhClient.DeleteNodePool(ctx, poolID); err != nil {
-			RecordNodeDeletion("delete_error")
-			RecordPoolOperation("delete", "error")
-			return fmt.Errorf("deleting pool: %w", err)
-		}
-		RecordPoolOperation("delete", "success")
-		// Clear from cache
-		c.mu.Lock()
-		for name, id := range c.poolCache {
-			if id == poolID {
-				delete(c.poolCache, name)
-				break
-			}
-		}
-		c.mu.Unlock()
-	} else if nodeID != "" {
-		// Delete the specific node using the OVH API
-		// This is more precise than scaling down, which lets OVH choose which node to remove
-		if err := c.ovhClient.DeleteNode(ctx, nodeID); err != nil {
-			// If specific node deletion fails, fall back to scaling down
-			logger.Info("Specific node deletion failed, falling back to scale down", "error", err)
-			_, err := c.ovhClient.UpdateNodePool(ctx, poolID, &ovhclient.UpdateNodePoolRequest{
-				DesiredNodes: pool.DesiredNodes - 1,
-			})
-			if err != nil {
-				RecordNodeDeletion("scale_down_error")
-				RecordPoolOperation("scale_down", "error")
-				return fmt.Errorf("scaling down pool: %w", err)
-			}
-			RecordPoolOperation("scale_down", "success")
-		} else {
-			RecordPoolOperation("delete_node", "success")
+	target, found := lo.Find(nodes, func(node ovhclient.Node) bool {
+		return (nodeID != "" && node.ID == nodeID) || (instanceID != "" && node.InstanceID == instanceID)
+	})
+	if !found {
+		// The node is confirmed gone
+		c.inventory.RemoveNode(poolID, nodeID)
+		c.releaseNode(poolID, nodeID)
+		RecordNodeDeletion("success")
+		if !nodeClaim.DeletionTimestamp.IsZero() {
+			RecordNodeDeletionDuration(time.Since(nodeClaim.DeletionTimestamp.Time).Seconds())
 		}
-	} else {
-		// No node ID, fall back to scaling down
-		logger.Info("No node ID annotation, falling back to scale down")
-		_, err := c.ovhClient.UpdateNodePool(ctx, poolID, &ovhclient.UpdateNodePoolRequest{
-			DesiredNodes: pool.DesiredNodes - 1,
-		})
-		if err != nil {
-			RecordNodeDeletion("scale_down_error")
-			RecordPoolOperation("scale_down", "error")
-			return fmt.Errorf("scaling down pool: %w", err)
+		return cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("instance terminated"))
+	}
+	if target.Status == NodeStatusDeleting {
+		logger.V(1).Info("Waiting for node deletion", "nodeID", target.ID, "poolID", poolID)
+		return nil
+	}
+
+	// Delete the pool with its last node, unless a launch is scaling the pool up
+	if deleted, err := c.deletePoolOfLastNode(ctx, poolID, nodes); err != nil || deleted {
+		return err
+	}
+
+	logger.Info("Deleting node", "nodeID", target.ID, "node", target.Name, "poolID", poolID)
+	if err := c.ovhClient.DeleteNode(ctx, target.ID); err != nil {
+		if ovhclient.IsNotFound(err) {
+			c.inventory.RemoveNode(poolID, target.ID)
+			c.releaseNode(poolID, target.ID)
+			RecordNodeDeletion("success")
+			return cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("instance terminated"))
 		}
-		RecordPoolOperation("scale_down", "success")
+		// The targeted delete is retried by Karpenter on the next call
+		RecordNodeDeletion("delete_error")
+		RecordPoolOperation("delete_node", "error")
+		return fmt.Errorf("deleting node %s: %w", target.ID, err)
 	}
+	RecordPoolOperation("delete_node", "success")
 
-	// Record successful deletion metrics
-	duration := time.Since(startTime).Seconds()
-	RecordNodeDeletion("success")
-	RecordNodeDeletionDuration(duration)
+	// Keep serving the node from the inventory until MKS stops listing it
+	target.Status = NodeStatusDeleting
+	c.inventory.PutNode(poolID, target)
+	return nil
+}
 
-	return cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("instance terminated"))
+// deletePoolOfLastNode deletes a pool whose only node is being deleted
+// Pools that are scaled up, or have queued scale ups, are kept for the launches in flight.
+func (c *CloudProvider) deletePoolOfLastNode(ctx context.Context, poolID string, nodes []ovhclient.Node) (bool, error) {
+	if len(nodes) != 1 {
+		return false, nil
+	}
+
+	// Serialized with getOrCreatePool so that no launch picks the pool while it is deleted
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pool, err := c.ovhClient.GetNodePool(ctx, poolID)
+	if err != nil {
+		RecordNodeDeletion("pool_error")
+		return false, fmt.Errorf("getting pool: %w", err)
+	}
+	if pool.Status == PoolStatusDeleting {
+		return true, nil
+	}
+	if pool.DesiredNodes > 1 || c.scaler.Pending(poolID) > 0 {
+		return false, nil
+	}
+
+	log.FromContext(ctx).Info("Deleting pool with its last node", "poolID", poolID, "pool", pool.Name, "nodeID", nodes[0].ID)
+	if err := c.ovhClient.DeleteNodePool(ctx, poolID); err != nil {
+		RecordNodeDeletion("delete_error")
+		RecordPoolOperation("delete", "error")
+		return false, fmt.Errorf("deleting pool: %w", err)
+	}
+	RecordPoolOperation("delete", "success")
+	for name, id := range c.poolCache {
+		if id == poolID {
+			delete(c.poolCache, name)
+			break
+		}
+	}
+	delete(c.claimedNodes, poolID)
+	delete(c.abandonedLaunches, poolID)
+	c.inventory.RemovePool(poolID)
+	return true, nil
 }
 
 // Get retrieves a NodeClaim by provider ID
@@ -248,53 +352,31 @@ func (c *CloudProvider) Get(ctx context.Context, providerID string) (*v1.NodeCla
 		return nil, cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("invalid provider ID format: %s", providerID))
 	}
 
-	// Search all Karpenter pools for the node with this instanceId
-	pools, err := c.ovhClient.ListNodePools(ctx)
+	// Look the instance up in the pool inventory
+	node, pool, err := c.inventory.FindInstance(ctx, instanceID)
 	if err != nil {
-		return nil, cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("listing pools: %w", err))
+		// Not a NotFound: Karpenter would otherwise garbage collect healthy nodes during an API outage
+		return nil, fmt.Errorf("reading pool inventory: %w", err)
 	}
-
-	for _, pool := range pools {
-		if !strings.HasPrefix(pool.Name, PoolNamePrefix) {
-			continue
-		}
-
-		nodes, err := c.ovhClient.ListPoolNodes(ctx, pool.ID)
-		if err != nil {
-			continue
-		}
-
-		for _, node := range nodes {
-			if node.InstanceID == instanceID {
-				return c.nodeToNodeClaim(&node, pool.ID)
-			}
-		}
+	if node == nil {
+		return nil, cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("node not found"))
 	}
 
-	return nil, cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("node not found"))
+	return c.nodeToNodeClaim(node, pool)
 }
 
 // List retrieves all Karpenter-managed NodeClaims
 func (c *CloudProvider) List(ctx context.Context) ([]*v1.NodeClaim, error) {
-	pools, err := c.ovhClient.ListNodePools(ctx)
+	// The inventory only holds Karpenter-managed pools
+	pools, err := c.inventory.Pools(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("listing pools: %w", err)
+		return nil, fmt.Errorf("reading pool inventory: %w", err)
 	}
 
 	var nodeClaims []*v1.NodeClaim
 	for _, pool := range pools {
-		// Only list Karpenter-managed pools
-		if !strings.HasPrefix(pool.Name, PoolNamePrefix) {
-			continue
-		}
-
-		nodes, err := c.ovhClient.ListPoolNodes(ctx, pool.ID)
-		if err != nil {
-			continue
-		}
-
-		for _, node := range nodes {
-			nc, err := c.nodeToNodeClaim(&node, pool.ID)
+		for _, node := range c.inventory.Nodes(pool.ID) {
+			nc, err := c.nodeToNodeClaim(&node, &pool)
 			if err != nil {
 				continue
 			}
@@ -306,9 +388,20 @@ func (c *CloudProvider) List(ctx context.Context) ([]*v1.NodeClaim, error) {
 }
 
 // GetInstanceTypes returns available instance types
+// The overhead of each instance type reflects the NodePool's NodeClass overhead configuration
 func (c *CloudProvider) GetInstanceTypes(ctx context.Context, nodePool *v1.NodePool) ([]*cloudprovider.InstanceType, error) {
 	SetInstanceTypesAvailable(len(c.instanceTypes))
-	return c.instanceTypes, nil
+	if nodePool == nil || nodePool.Spec.Template.Spec.NodeClassRef == nil {
+		return c.instanceTypesForNodeClass(ctx, nil), nil
+	}
+
+	nodeClass := &v1alpha1.OVHNodeClass{}
+	if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: nodePool.Spec.Template.Spec.NodeClassRef.Name}, nodeClass); err != nil {
+		// Fall back to the computed overhead, the NodeClass readiness is checked on Create
+		log.FromContext(ctx).V(1).Info("Cannot resolve NodeClass for instance types", "nodePool", nodePool.Name, "error", err)
+		return c.instanceTypesForNodeClass(ctx, nil), nil
+	}
+	return c.instanceTypesForNodeClass(ctx, nodeClass), nil
 }
 
 // IsDrifted checks if a NodeClaim has drifted from its NodeClass
@@ -318,6 +411,17 @@ func (c *CloudProvider) GetInstanceTypes(ctx context.Context, nodePool *v1.NodeP
 func (c *CloudProvider) IsDrifted(ctx context.Context, nodeClaim *v1.NodeClaim) (cloudprovider.DriftReason, error) {
 	logger := log.FromContext(ctx)
 
+	// Check interruption
+	// The interruption controller marks the NodeClaims of instances about to be maintained or migrated.
+	// Reporting them as drifted lets Karpenter replace them within the NodePool disruption budgets.
+	if kind, ok := nodeClaim.Annotations[v1alpha1.AnnotationInterruption]; ok {
+		logger.Info("Drift detected: Interrupted",
+			"nodeClaim", nodeClaim.Name,
+			"kind", kind)
+		RecordDriftDetection("Interrupted")
+		return "Interrupted", nil
+	}
+
 	nodeClass, err := c.resolveNodeClass(ctx, nodeClaim)
 	if err != nil {
 		// If we can't resolve the NodeClass, don't report drift
@@ -366,6 +470,55 @@ func (c *CloudProvider) IsDrifted(ctx context.Context, nodeClaim *v1.NodeClaim)
 		return "AntiAffinityChanged", nil
 	}
 
+	// Check pool template drift
+	// Tags, labels and taints are baked into the pool template when the pool is created. The NodeClass
+	// hash detects NodeClass changes, and the template hash recomputed from the pool detects edits of
+	// the template itself. NodePool template changes are detected by Karpenter from the NodePool hash.
+	// Pools created before hashes were stamped are skipped.
+	metadata := parsePoolMetadata(pool)
+	if metadata.NodeClassHash != "" && metadata.NodeClassHash != nodeClassHash(nodeClass) {
+		logger.Info("Drift detected: NodeClassDrifted",
+			"nodeClaim", nodeClaim.Name,
+			"pool", pool.Name,
+			"poolNodeClassHash", metadata.NodeClassHash,
+			"nodeClassHash", nodeClassHash(nodeClass))
+		RecordDriftDetection("NodeClassDrifted")
+		return "NodeClassDrifted", nil
+	}
+	if metadata.Hash != "" && pool.Template != nil {
+		actual := poolTemplateHash(metadata.NodeClass, metadata.NodePool, pool.MonthlyBilled, pool.AntiAffinity, pool.Template)
+		if actual != metadata.Hash {
+			logger.Info("Drift detected: PoolTemplateDrifted",
+				"nodeClaim", nodeClaim.Name,
+				"pool", pool.Name,
+				"poolHash", metadata.Hash,
+				"templateHash", actual)
+			RecordDriftDetection("PoolTemplateDrifted")
+			return "PoolTemplateDrifted", nil
+		}
+	}
+
+	// Check Kubernetes version drift
+	// After a control-plane upgrade MKS reports the nodes still running the previous version
+	if nodeClass.Spec.UpgradePolicy == v1alpha1.UpgradePolicyReplace && nodeClaim.Status.ProviderID != "" {
+		instanceID := strings.TrimPrefix(nodeClaim.Status.ProviderID, ProviderPrefix)
+		node, _, err := c.inventory.FindInstance(ctx, instanceID)
+		if err != nil {
+			logger.V(1).Info("Cannot get node for version drift detection", "nodeClaim", nodeClaim.Name, "error", err)
+			return "", nil
+		}
+		// Nodes still being installed or reinstalled report an outdated version until they are READY
+		if node != nil && node.Status == NodeStatusReady && !node.IsUpToDate {
+			logger.Info("Drift detected: KubernetesVersionDrifted",
+				"nodeClaim", nodeClaim.Name,
+				"pool", pool.Name,
+				"node", node.Name,
+				"nodeVersion", node.Version)
+			RecordDriftDetection("KubernetesVersionDrifted")
+			return "KubernetesVersionDrifted", nil
+		}
+	}
+
 	// No OVHcloud-specific drift detected
 	// Note: Karpenter core may still detect RequirementsDrifted if node labels
 	// don't match the NodeClaim requirements. This is expected behavior when
@@ -384,19 +537,10 @@ func (c *CloudProvider) GetSupportedNodeClasses() []status.Object {
 }
 
 // RepairPolicies returns the repair policies for unhealthy nodes
+// The policies of each NodeClass are evaluated by the node health controller, which sets the
+// RepairRequested condition on the nodes to repair.
 func (c *CloudProvider) RepairPolicies() []cloudprovider.RepairPolicy {
-	return []cloudprovider.RepairPolicy{
-		{
-			ConditionType:      corev1.NodeReady,
-			ConditionStatus:    corev1.ConditionFalse,
-			TolerationDuration: 10 * time.Minute,
-		},
-		{
-			ConditionType:      corev1.NodeReady,
-			ConditionStatus:    corev1.ConditionUnknown,
-			TolerationDuration: 10 * time.Minute,
-		},
-	}
+	return []cloudprovider.RepairPolicy{repairRequestedRepairPolicy}
 }
 
 // Helper methods
@@ -409,39 +553,7 @@ func (c *CloudProvider) resolveNodeClass(ctx context.Context, nodeClaim *v1.Node
 	return nodeClass, nil
 }
 
-func (c *CloudProvider) poolName(flavor, zone string) string {
-	// Sanitize flavor name for pool naming
-	safeFlavor := strings.ReplaceAll(flavor, ".", "-")
-	if zone != "" {
-		return fmt.Sprintf("%s%s-%s", PoolNamePrefix, safeFlavor, zone)
-	}
-	return fmt.Sprintf("%s%s", PoolNamePrefix, safeFlavor)
-}
-
-func (c *CloudProvider) selectFlavor(nodeClaim *v1.NodeClaim) (string, error) {
-	// Find the instance type requirement
-	for _, req := range nodeClaim.Spec.Requirements {
-		if req.Key == corev1.LabelInstanceTypeStable && len(req.Values) > 0 {
-			// Return the first matching instance type
-			return req.Values[0], nil
-		}
-	}
-	return "", fmt.Errorf("no instance type requirement found")
-}
-
-func (c *CloudProvider) selectZone(nodeClaim *v1.NodeClaim) string {
-	for _, req := range nodeClaim.Spec.Requirements {
-		if req.Key == corev1.LabelTopologyZone && len(req.Values) > 0 {
-			return req.Values[0]
-		}
-	}
-	// Default to zone "a" of the configured region
-	// OVHcloud availability zones follow the pattern: {region}-a, {region}-b, {region}-c
-	region := c.ovhClient.GetRegion()
-	return strings.ToLower(region) + "-a"
-}
-
-func (c *CloudProvider) getOrCreatePool(ctx context.Context, poolName, flavor, zone string, nodeClass *v1alpha1.OVHNodeClass, nodeClaim *v1.NodeClaim) (*ovhclient.NodePool, map[string]bool, error) {
+func (c *CloudProvider) getOrCreatePool(ctx context.Context, poolName string, metadata poolMetadata, nodeClass *v1alpha1.OVHNodeClass, template *ovhclient.NodePoolTemplate) (*ovhclient.NodePool, map[string]bool, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -459,19 +571,17 @@ func (c *CloudProvider) getOrCreatePool(ctx context.Context, poolName, flavor, z
 
 	// Check cache first
 	if poolID, ok := c.poolCache[poolName]; ok {
-		pool, err := c.ovhClient.GetNodePool(ctx, poolID)
+		_, err := c.ovhClient.GetNodePool(ctx, poolID)
 		if err == nil {
 			// Get existing node IDs BEFORE scaling up
 			existingNodeIDs := getExistingNodeIDs(poolID)
 
-			// Scale up the pool
-			_, err = c.ovhClient.UpdateNodePool(ctx, poolID, &ovhclient.UpdateNodePoolRequest{
-				DesiredNodes: pool.DesiredNodes + 1,
-			})
+			// Scale up the pool, or queue the change until the pool is READY
+			pool, err := c.scaler.Scale(ctx, poolID, 1)
 			if err != nil {
 				return nil, nil, fmt.Errorf("scaling up pool: %w", err)
 			}
-			pool.DesiredNodes++
+			c.inventory.PutPool(*pool)
 			return pool, existingNodeIDs, nil
 		}
 		// Pool might have been deleted, remove from cache
@@ -492,32 +602,60 @@ func (c *CloudProvider) getOrCreatePool(ctx context.Context, poolName, flavor, z
 			// Get existing node IDs BEFORE scaling up
 			existingNodeIDs := getExistingNodeIDs(pool.ID)
 
-			// Scale up
-			_, err = c.ovhClient.UpdateNodePool(ctx, pool.ID, &ovhclient.UpdateNodePoolRequest{
-				DesiredNodes: pool.DesiredNodes + 1,
-			})
+			// Scale up, or queue the change until the pool is READY
+			scaled, err := c.scaler.Scale(ctx, pool.ID, 1)
 			if err != nil {
 				return nil, nil, fmt.Errorf("scaling up existing pool: %w", err)
 			}
-			pool.DesiredNodes++
-			return &pool, existingNodeIDs, nil
+			c.inventory.PutPool(*scaled)
+			return scaled, existingNodeIDs, nil
 		}
 	}
 
 	// Create new pool
 	req := &ovhclient.CreateNodePoolRequest{
 		Name:          poolName,
-		FlavorName:    flavor,
+		FlavorName:    metadata.Flavor,
 		DesiredNodes:  DefaultDesiredNodes,
 		Autoscale:     false,
 		MonthlyBilled: nodeClass.Spec.MonthlyBilled,
 		AntiAffinity:  nodeClass.Spec.AntiAffinity,
 	}
 
-	if zone != "" {
-		req.AvailabilityZones = []string{zone}
+	if metadata.Zone != "" {
+		req.AvailabilityZones = []string{metadata.Zone}
+	}
+
+	// Pool metadata (flavor, zone, NodeClass, NodePool, billing, hash) is read back by Get and List
+	labels := make(map[string]string, len(template.Metadata.Labels))
+	for k, v := range template.Metadata.Labels {
+		labels[k] = v
+	}
+	for k, v := range metadata.Labels() {
+		labels[k] = v
+	}
+	req.Template = &ovhclient.NodePoolTemplate{
+		Metadata: ovhclient.NodePoolTemplateMetadata{
+			Labels:      labels,
+			Annotations: template.Metadata.Annotations,
+			Finalizers:  template.Metadata.Finalizers,
+		},
+		Spec: template.Spec,
+	}
+
+	pool, err := c.ovhClient.CreateNodePool(ctx, req)
+	if err != nil {
+		return c.adoptPoolAfterCreateFailure(ctx, poolName, err, getExistingNodeIDs)
 	}
 
+	c.poolCache[poolName] = pool.ID
+	c.inventory.PutPool(*pool)
+	// For a new pool, there are no existing nodes
+	return pool, make(map[string]bool), nil
+}
+
+// poolTemplate builds the template of the pool a NodeClaim is launched in, without the pool metadata labels
+func (c *CloudProvider) poolTemplate(flavor string, nodeClass *v1alpha1.OVHNodeClass, nodeClaim *v1.NodeClaim) *ovhclient.NodePoolTemplate {
 	// Build labels for the node template
 	// These labels are applied to nodes by MKS and are critical for Karpenter to match nodes to NodeClaims
 	labels := make(map[string]string)
@@ -527,8 +665,6 @@ func (c *CloudProvider) getOrCreatePool(ctx context.Context, poolName, flavor, z
 
 	// Standard Kubernetes labels that Karpenter uses for scheduling decisions
 	// These MUST match what we set on the NodeClaim for drift detection to work correctly
-	labels[corev1.LabelInstanceTypeStable] = flavor
-	labels[corev1.LabelTopologyZone] = zone
 	labels[v1.CapacityTypeLabelKey] = v1.CapacityTypeOnDemand
 	labels[corev1.LabelArchStable] = v1.ArchitectureAmd64
 	labels[corev1.LabelOSStable] = string(corev1.Linux)
@@ -536,10 +672,11 @@ func (c *CloudProvider) getOrCreatePool(ctx context.Context, poolName, flavor, z
 	// Add Karpenter-specific labels
 	labels["karpenter.sh/registered"] = "true"
 
-	// Add the NodePool name from the NodeClaim if available
-	if nodeClaim != nil && nodeClaim.Labels != nil {
-		if nodePoolName, ok := nodeClaim.Labels[v1.NodePoolLabelKey]; ok {
-			labels[v1.NodePoolLabelKey] = nodePoolName
+	// Instance labels derived from the flavor (cpu, memory, family, size, ...)
+	// These let NodePool authors use Gt/Lt constraints instead of enumerating flavors
+	if instanceType, err := c.getInstanceType(flavor); err == nil {
+		for k, v := range instanceTypeLabels(instanceType) {
+			labels[k] = v
 		}
 	}
 
@@ -548,6 +685,11 @@ func (c *CloudProvider) getOrCreatePool(ctx context.Context, poolName, flavor, z
 		labels[k] = v
 	}
 
+	// NodePool template labels and single-value requirements, which pods select nodes on
+	for k, v := range nodeClaimLabels(nodeClaim) {
+		labels[k] = v
+	}
+
 	// Build taints from NodeClaim spec
 	// OVHcloud MKS API requires taints to be set (even if empty)
 	taints := []corev1.Taint{}
@@ -560,7 +702,7 @@ func (c *CloudProvider) getOrCreatePool(ctx context.Context, poolName, flavor, z
 	annotations := make(map[string]string)
 	finalizers := []string{}
 
-	req.Template = &ovhclient.NodePoolTemplate{
+	return &ovhclient.NodePoolTemplate{
 		Metadata: ovhclient.NodePoolTemplateMetadata{
 			Labels:      labels,
 			Annotations: annotations,
@@ -570,48 +712,249 @@ func (c *CloudProvider) getOrCreatePool(ctx context.Context, poolName, flavor, z
 			Taints: taints,
 		},
 	}
+}
 
-	pool, err := c.ovhClient.CreateNodePool(ctx, req)
+// adoptPoolAfterCreateFailure resolves a failed pool creation by looking the pool up by name
+// On a conflict the pool was created concurrently, so it is scaled up for this NodeClaim.
+// On an ambiguous failure (timeout, server error) the creation may have been applied, in which
+// case the pool already requests the node of this NodeClaim and is adopted as is.
+// Callers must hold c.mu.
+func (c *CloudProvider) adoptPoolAfterCreateFailure(ctx context.Context, poolName string, createErr error, getExistingNodeIDs func(string) map[string]bool) (*ovhclient.NodePool, map[string]bool, error) {
+	conflict := ovhclient.IsConflict(createErr)
+	if !conflict && !ovhclient.IsAmbiguous(createErr) {
+		return nil, nil, fmt.Errorf("creating pool: %w", createErr)
+	}
+
+	pools, err := c.ovhClient.ListNodePools(ctx)
 	if err != nil {
-		return nil, nil, fmt.Errorf("creating pool: %w", err)
+		return nil, nil, fmt.Errorf("creating pool: %w (listing pools to resolve it: %v)", createErr, err)
+	}
+	pool, found := lo.Find(pools, func(p ovhclient.NodePool) bool { return p.Name == poolName })
+	if !found {
+		return nil, nil, fmt.Errorf("creating pool: %w", createErr)
 	}
 
+	log.FromContext(ctx).Info("Adopting existing pool after create failure", "poolName", poolName, "poolID", pool.ID, "conflict", conflict, "error", createErr)
+	RecordPoolOperation("adopt", "success")
 	c.poolCache[poolName] = pool.ID
-	// For a new pool, there are no existing nodes
-	return pool, make(map[string]bool), nil
+
+	if !conflict {
+		c.inventory.PutPool(pool)
+		return &pool, make(map[string]bool), nil
+	}
+
+	existingNodeIDs := getExistingNodeIDs(pool.ID)
+	scaled, err := c.scaler.Scale(ctx, pool.ID, 1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("scaling up adopted pool: %w", err)
+	}
+	c.inventory.PutPool(*scaled)
+	return scaled, existingNodeIDs, nil
 }
 
-func (c *CloudProvider) waitForNewNode(ctx context.Context, poolID string, existingNodeIDs map[string]bool) (*ovhclient.Node, error) {
-	timeout := time.After(10 * time.Minute)
+// launch gets or creates the pool of a flavor in a zone and waits for its new node
+func (c *CloudProvider) launch(ctx context.Context, nodeClaim *v1.NodeClaim, nodeClass *v1alpha1.OVHNodeClass, flavor, zone string, price float64) (*ovhclient.NodePool, *ovhclient.Node, error) {
+	template := c.poolTemplate(flavor, nodeClass, nodeClaim)
+	metadata := newPoolMetadata(flavor, zone, nodeClass, nodeClaim, template)
+	poolName := metadata.PoolName()
+	log.FromContext(ctx).Info("Creating node", "flavor", flavor, "zone", zone, "price", price, "poolName", poolName, "poolHash", metadata.Hash)
+
+	// Get or create the pool with labels and taints from NodeClaim
+	// Also get the existing node IDs BEFORE scaling up, so we can identify the NEW node
+	pool, existingNodeIDs, err := c.getOrCreatePool(ctx, poolName, metadata, nodeClass, template)
+	if err != nil {
+		if ovhclient.IsCapacityError(err) {
+			RecordNodeProvisioning(flavor, zone, "insufficient_capacity")
+		} else {
+			RecordNodeProvisioning(flavor, zone, "pool_error")
+		}
+		return nil, nil, fmt.Errorf("getting/creating pool: %w", err)
+	}
+
+	// Wait for a new node to appear (one that wasn't in existingNodeIDs)
+	node, err := c.waitForNewNode(ctx, pool.ID, existingNodeIDs, launchTimeout(nodeClass))
+	if err != nil {
+		RecordNodeProvisioning(flavor, zone, "launch_failed")
+		return nil, nil, fmt.Errorf("waiting for new node: %w", err)
+	}
+
+	// Make the new node visible to Get and List before the next inventory refresh
+	c.inventory.PutPool(*pool)
+	c.inventory.PutNode(pool.ID, *node)
+	return pool, node, nil
+}
+
+// waitForNewNode waits for the node a pool was scaled up for to become READY
+// The launch claims the first new node no other launch has claimed, and only that node is
+// deleted when it fails or is not READY within the timeout.
+func (c *CloudProvider) waitForNewNode(ctx context.Context, poolID string, existingNodeIDs map[string]bool, timeout time.Duration) (*ovhclient.Node, error) {
+	deadline := time.After(timeout)
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
 
+	// New node claimed by this launch
+	var claimed *ovhclient.Node
+	c.startWaiting(poolID)
+	defer func() {
+		if claimed == nil {
+			c.stopWaiting(poolID)
+		}
+	}()
 	for {
 		select {
 		case <-ctx.Done():
+			c.abandonLaunch(context.WithoutCancel(ctx), poolID, existingNodeIDs, claimed)
 			return nil, ctx.Err()
-		case <-timeout:
-			return nil, fmt.Errorf("timeout waiting for new node")
+		case <-deadline:
+			c.abandonLaunch(ctx, poolID, existingNodeIDs, claimed)
+			if claimed != nil {
+				return nil, fmt.Errorf("%w: node %s still %s after %s", errNodeLaunchFailed, claimed.Name, claimed.Status, timeout)
+			}
+			return nil, fmt.Errorf("%w: no node appeared after %s", errNodeLaunchFailed, timeout)
 		case <-ticker.C:
+			// Apply desired-count changes queued while the pool was converging
+			if c.scaler.Pending(poolID) != 0 {
+				if _, err := c.scaler.Flush(ctx, poolID); err != nil {
+					log.FromContext(ctx).V(1).Info("Failed to apply queued pool scaling", "poolID", poolID, "error", err)
+				}
+			}
+
 			nodes, err := c.ovhClient.ListPoolNodes(ctx, poolID)
 			if err != nil {
 				continue
 			}
 
-			// Look for a READY node with InstanceID populated that wasn't in the original set
-			// This ensures we return the NEW node created for this NodeClaim, not an existing one
-			for _, node := range nodes {
-				if node.Status == "READY" && node.InstanceID != "" {
-					// Check if this is a NEW node (not in the existing set)
-					if !existingNodeIDs[node.ID] {
-						return &node, nil
-					}
-				}
+			node, found := c.claimNewNode(poolID, nodes, existingNodeIDs, claimed)
+			if !found {
+				continue
+			}
+			claimed = &node
+			switch {
+			case node.Status == NodeStatusReady && node.InstanceID != "":
+				return &node, nil
+			case isFailedNodeStatus(node.Status):
+				c.abandonLaunch(ctx, poolID, existingNodeIDs, claimed)
+				return nil, fmt.Errorf("%w: node %s is %s", errNodeLaunchFailed, node.Name, node.Status)
 			}
 		}
 	}
 }
 
+// claimNewNode returns the node claimed by a launch, claiming one first if the launch has none
+// Nodes listed before the launch scaled the pool, or claimed by another launch of the same
+// pool, are never claimed, so that a node belongs to a single NodeClaim.
+func (c *CloudProvider) claimNewNode(poolID string, nodes []ovhclient.Node, existingNodeIDs map[string]bool, claimed *ovhclient.Node) (ovhclient.Node, bool) {
+	if claimed != nil {
+		return lo.Find(nodes, func(node ovhclient.Node) bool { return node.ID == claimed.ID })
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, node := range nodes {
+		if existingNodeIDs[node.ID] || c.claimedNodes[poolID][node.ID] {
+			continue
+		}
+		if c.claimedNodes[poolID] == nil {
+			c.claimedNodes[poolID] = make(map[string]bool)
+		}
+		c.claimedNodes[poolID][node.ID] = true
+		c.waitingLaunches[poolID]--
+		if c.waitingLaunches[poolID] <= 0 {
+			delete(c.waitingLaunches, poolID)
+		}
+		return node, true
+	}
+	return ovhclient.Node{}, false
+}
+
+// startWaiting counts a launch waiting for a node of its pool
+func (c *CloudProvider) startWaiting(poolID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waitingLaunches[poolID]++
+}
+
+// stopWaiting stops counting a launch that leaves without claiming a node
+func (c *CloudProvider) stopWaiting(poolID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waitingLaunches[poolID]--
+	if c.waitingLaunches[poolID] <= 0 {
+		delete(c.waitingLaunches, poolID)
+	}
+}
+
+// releaseNode drops the claim on a node that is gone from its pool
+func (c *CloudProvider) releaseNode(poolID, nodeID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.claimedNodes[poolID], nodeID)
+	if len(c.claimedNodes[poolID]) == 0 {
+		delete(c.claimedNodes, poolID)
+	}
+}
+
+// releasePool drops the claims on the nodes of a deleted pool
+func (c *CloudProvider) releasePool(poolID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.claimedNodes, poolID)
+}
+
+// abandonLaunch gives back the capacity a failed launch added to its pool
+// Only the node claimed by the launch is deleted, which also lowers the desired count. Without a
+// node to delete, the scale up is withdrawn while still queued. Once applied, lowering the desired
+// count would let MKS pick the node to remove, which may be a node in use, so the launch is recorded
+// and its node is deleted by the LaunchReaper once it shows up.
+func (c *CloudProvider) abandonLaunch(ctx context.Context, poolID string, existingNodeIDs map[string]bool, claimed *ovhclient.Node) {
+	if claimed != nil {
+		if !c.deleteFailedNode(ctx, poolID, claimed) {
+			c.recordAbandonedLaunch(poolID, abandonedLaunch{node: claimed, at: time.Now()})
+		}
+		return
+	}
+	if c.scaler.Pending(poolID) > 0 {
+		c.scaler.Cancel(poolID, 1)
+		return
+	}
+	log.FromContext(ctx).Info("Deleting the node of the abandoned launch once it appears", "poolID", poolID)
+	c.recordAbandonedLaunch(poolID, abandonedLaunch{existingNodeIDs: existingNodeIDs, at: time.Now()})
+}
+
+// deleteFailedNode removes a node that failed to launch, so that it does not stay in the pool
+// with the desired count it was scaled up for
+func (c *CloudProvider) deleteFailedNode(ctx context.Context, poolID string, node *ovhclient.Node) bool {
+	log.FromContext(ctx).Info("Deleting node that failed to launch", "nodeID", node.ID, "node", node.Name, "status", node.Status)
+	if err := c.ovhClient.DeleteNode(ctx, node.ID); err != nil && !ovhclient.IsNotFound(err) {
+		RecordPoolOperation("delete_node", "error")
+		log.FromContext(ctx).Error(err, "Failed to delete node that failed to launch", "nodeID", node.ID)
+		return false
+	}
+	RecordPoolOperation("delete_node", "success")
+	c.releaseNode(poolID, node.ID)
+	return true
+}
+
+// launchTimeout returns how long a node of the NodeClass may take to become READY
+func launchTimeout(nodeClass *v1alpha1.OVHNodeClass) time.Duration {
+	if nodeClass.Spec.LaunchTimeout != nil && nodeClass.Spec.LaunchTimeout.Duration > 0 {
+		return nodeClass.Spec.LaunchTimeout.Duration
+	}
+	return DefaultLaunchTimeout
+}
+
+// instanceTypeLabels returns the labels of the single-valued requirements of an instance type
+// Requirements.Labels() cannot be used: it leaves out well-known labels, which all instance labels are
+func instanceTypeLabels(it *cloudprovider.InstanceType) map[string]string {
+	labels := make(map[string]string)
+	for key, requirement := range it.Requirements {
+		if requirement.Operator() == corev1.NodeSelectorOpIn && requirement.Len() == 1 {
+			labels[key] = requirement.Values()[0]
+		}
+	}
+	return labels
+}
+
 func (c *CloudProvider) getInstanceType(name string) (*cloudprovider.InstanceType, error) {
 	it, found := lo.Find(c.instanceTypes, func(it *cloudprovider.InstanceType) bool {
 		return it.Name == name
@@ -622,6 +965,70 @@ func (c *CloudProvider) getInstanceType(name string) (*cloudprovider.InstanceTyp
 	return it, nil
 }
 
+// instanceTypesForNodeClass returns the instance types with their overhead and offerings adjusted
+// Allocatable resources observed on registered Nodes take precedence over the modelled overhead,
+// which itself applies the NodeClass overhead overrides. Flavors without registered Nodes use the
+// reservations MKS applied to the registered Nodes of other flavors, before the GKE estimate. Offerings are priced from the current
+// pricing catalog, and those that recently failed to launch are reported as unavailable so that
+// the scheduler stops picking them.
+func (c *CloudProvider) instanceTypesForNodeClass(ctx context.Context, nodeClass *v1alpha1.OVHNodeClass) []*cloudprovider.InstanceType {
+	var overrides *v1alpha1.OverheadConfiguration
+	if nodeClass != nil {
+		overrides = nodeClass.Spec.Overhead
+	}
+	if overrides == nil && c.pricingClient == nil && c.allocatableCache.Len() == 0 && c.unavailableOfferings.Len() == 0 {
+		return c.instanceTypes
+	}
+	points := reservationPoints(c.instanceTypes, c.allocatableCache)
+	return lo.Map(c.instanceTypes, func(it *cloudprovider.InstanceType, _ int) *cloudprovider.InstanceType {
+		overhead := computeOverhead(it.Capacity, overrides)
+		if observed, ok := c.allocatableCache.Get(it.Name); ok {
+			overhead = observedOverhead(it.Capacity, observed, overhead)
+		} else if learned := learnedAllocatable(it.Capacity, points, overrides); len(learned) > 0 {
+			overhead = observedOverhead(it.Capacity, learned, overhead)
+		}
+		return &cloudprovider.InstanceType{
+			Name:         it.Name,
+			Requirements: it.Requirements,
+			Offerings:    c.availableOfferings(ctx, it),
+			Capacity:     it.Capacity,
+			Overhead:     overhead,
+		}
+	})
+}
+
+// availableOfferings returns the offerings of an instance type at their current price, marking those
+// that recently failed to launch unavailable
+// Instance types are built once at startup, possibly before the pricing catalog was loaded, and the
+// catalog is refreshed while the controller runs, so offering prices are read from the pricing client.
+func (c *CloudProvider) availableOfferings(ctx context.Context, it *cloudprovider.InstanceType) cloudprovider.Offerings {
+	price, priced := c.currentPrice(ctx, it.Name)
+	return lo.Map(it.Offerings, func(offering *cloudprovider.Offering, _ int) *cloudprovider.Offering {
+		zone := offering.Requirements.Get(corev1.LabelTopologyZone).Any()
+		unavailable := offering.Available && c.unavailableOfferings.IsUnavailable(it.Name, zone)
+		if !unavailable && (!priced || offering.Price == price) {
+			return offering
+		}
+		adjusted := *offering
+		if priced {
+			adjusted.Price = price
+		}
+		if unavailable {
+			adjusted.Available = false
+		}
+		return &adjusted
+	})
+}
+
+// currentPrice returns the hourly price of a flavor in the current pricing catalog
+// Flavors the catalog does not price keep the price their offerings were built with
+func (c *CloudProvider) currentPrice(ctx context.Context, flavor string) (float64, bool) {
+	if c.pricingClient == nil {
+		return 0, false
+	}
+	return c.pricingClient.CatalogPrice(ctx, flavor, c.ovhClient.GetRegion())
+}
+
 func (c *CloudProvider) getCapacityForFlavor(instanceType *cloudprovider.InstanceType) corev1.ResourceList {
 	if instanceType == nil {
 		return corev1.ResourceList{}
@@ -636,27 +1043,24 @@ func (c *CloudProvider) getAllocatableForFlavor(instanceType *cloudprovider.Inst
 	return instanceType.Allocatable()
 }
 
-func (c *CloudProvider) nodeToNodeClaim(node *ovhclient.Node, poolID string) (*v1.NodeClaim, error) {
-	// Extract zone from pool name: karpenter-{flavor}-{zone}
-	// e.g., karpenter-b3-8-eu-west-par-a -> eu-west-par-a
-	zone := c.extractZoneFromPoolName(poolID)
-	if zone == "" {
-		// Fallback to default zone
-		region := c.ovhClient.GetRegion()
-		zone = strings.ToLower(region) + "-a"
+func (c *CloudProvider) nodeToNodeClaim(node *ovhclient.Node, pool *ovhclient.NodePool) (*v1.NodeClaim, error) {
+	metadata := parsePoolMetadata(pool)
+	flavor := node.Flavor
+	if flavor == "" {
+		flavor = metadata.Flavor
 	}
 
-	return &v1.NodeClaim{
+	nodeClaim := &v1.NodeClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: node.Name,
 			Annotations: map[string]string{
-				v1alpha1.AnnotationOVHPoolID:   poolID,
-				v1alpha1.AnnotationOVHNodeID:   node.ID,
-				v1alpha1.AnnotationOVHNodeName: node.Name,
+				v1alpha1.AnnotationOVHPoolID:        pool.ID,
+				v1alpha1.AnnotationOVHNodeID:        node.ID,
+				v1alpha1.AnnotationOVHNodeName:      node.Name,
+				v1alpha1.AnnotationOVHMonthlyBilled: strconv.FormatBool(pool.MonthlyBilled),
 			},
 			Labels: map[string]string{
-				corev1.LabelInstanceTypeStable: node.Flavor,
-				corev1.LabelTopologyZone:       zone,
+				corev1.LabelInstanceTypeStable: flavor,
 				v1.CapacityTypeLabelKey:        v1.CapacityTypeOnDemand,
 				corev1.LabelArchStable:         v1.ArchitectureAmd64,
 				corev1.LabelOSStable:           string(corev1.Linux),
@@ -666,34 +1070,24 @@ func (c *CloudProvider) nodeToNodeClaim(node *ovhclient.Node, poolID string) (*v
 			NodeName:   node.Name,
 			ProviderID: fmt.Sprintf("%s%s", ProviderPrefix, node.InstanceID),
 		},
-	}, nil
-}
-
-// extractZoneFromPoolName extracts the zone from a pool name or ID
-// Pool names follow: karpenter-{flavor}-{zone}
-func (c *CloudProvider) extractZoneFromPoolName(poolID string) string {
-	// First, try to get the pool to get its actual zone
-	ctx := context.Background()
-	pool, err := c.ovhClient.GetNodePool(ctx, poolID)
-	if err == nil && pool.AvailabilityZone != "" {
-		return pool.AvailabilityZone
 	}
 
-	// Fallback: try to find pool in cache and extract zone from name
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	for name, id := range c.poolCache {
-		if id == poolID {
-			// Extract zone from name: karpenter-{flavor}-{zone}
-			// Zone format is region-letter (e.g., eu-west-par-a)
-			parts := strings.Split(name, "-")
-			if len(parts) >= 4 {
-				// Reconstruct zone from last 4 parts (e.g., eu-west-par-a)
-				return strings.Join(parts[len(parts)-4:], "-")
-			}
+	// Topology is only reported when known, a guessed zone would mislead scheduling
+	if metadata.Zone != "" {
+		nodeClaim.Labels[corev1.LabelTopologyZone] = metadata.Zone
+	}
+	if metadata.NodePool != "" {
+		nodeClaim.Labels[v1.NodePoolLabelKey] = metadata.NodePool
+	}
+	if metadata.NodeClass != "" {
+		nodeClaim.Spec.NodeClassRef = &v1.NodeClassReference{
+			Group: apis.Group,
+			Kind:  "OVHNodeClass",
+			Name:  metadata.NodeClass,
 		}
 	}
-	return ""
+
+	return nodeClaim, nil
 }
 
 // ConstructInstanceTypes builds instance types from OVH flavors (uses estimated pricing)
@@ -711,7 +1105,21 @@ func ConstructInstanceTypesWithPricing(ctx context.Context, ovhClient *ovhclient
 	capFlavors, err := ovhClient.ListKubeFlavors(ctx, region)
 	if err == nil && len(capFlavors) > 0 {
 		logger.Info("Retrieved flavors from OVH Capabilities API", "region", region, "count", len(capFlavors))
-		return buildInstanceTypesFromCapabilities(ctx, capFlavors, region, pricingClient)
+
+		// The capabilities API does not report disk sizes, join them from the project flavor API
+		disks := make(map[string]int)
+		projectFlavors, err := ovhClient.ListProjectFlavors(ctx, region)
+		if err != nil {
+			logger.Info("Project flavor API unavailable, using default disk size", "error", err, "defaultDiskGiB", DefaultFlavorDiskGiB)
+		}
+		for _, pf := range projectFlavors {
+			if pf.OSType != "" && pf.OSType != "linux" {
+				continue
+			}
+			disks[pf.Name] = pf.Disk
+		}
+
+		return buildInstanceTypesFromCapabilities(ctx, capFlavors, disks, region, pricingClient)
 	}
 
 	// Fallback to cluster-specific endpoint
@@ -728,7 +1136,8 @@ func ConstructInstanceTypesWithPricing(ctx context.Context, ovhClient *ovhclient
 }
 
 // buildInstanceTypesFromCapabilities builds instance types from capabilities API response
-func buildInstanceTypesFromCapabilities(ctx context.Context, capFlavors []ovhclient.KubeFlavorCapability, region string, pricingClient *ovhclient.PricingClient) ([]*cloudprovider.InstanceType, error) {
+// disks maps flavor names to root disk sizes in GiB, as reported by the project flavor API
+func buildInstanceTypesFromCapabilities(ctx context.Context, capFlavors []ovhclient.KubeFlavorCapability, disks map[string]int, region string, pricingClient *ovhclient.PricingClient) ([]*cloudprovider.InstanceType, error) {
 	var instanceTypes []*cloudprovider.InstanceType
 
 	for _, capFlavor := range capFlavors {
@@ -744,6 +1153,7 @@ func buildInstanceTypesFromCapabilities(ctx context.Context, capFlavors []ovhcli
 			Category:  capFlavor.Category,
 			VCPUs:     capFlavor.VCPUs,
 			RAM:       capFlavor.RAM, // Already in GiB from capabilities API
+			Disk:      disks[capFlavor.Name],
 			GPUs:      capFlavor.GPUs,
 			Available: true,
 			State:     capFlavor.State,
@@ -775,14 +1185,45 @@ func buildInstanceTypesFromClusterFlavors(ctx context.Context, flavors []ovhclie
 
 // buildInstanceType creates a single InstanceType from a Flavor
 func buildInstanceType(ctx context.Context, flavor ovhclient.Flavor, region string, pricingClient *ovhclient.PricingClient, ramInGiB bool) *cloudprovider.InstanceType {
+	// Memory in MiB for the instance-memory label (cluster API already returns MiB)
+	memoryMiB := flavor.RAM
+	if ramInGiB {
+		memoryMiB = flavor.RAM * 1024
+	}
+
 	// Build requirements including GPU if present
+	info := parseFlavorName(flavor.Name)
 	requirements := scheduling.NewRequirements(
 		scheduling.NewRequirement(corev1.LabelInstanceTypeStable, corev1.NodeSelectorOpIn, flavor.Name),
 		scheduling.NewRequirement(corev1.LabelArchStable, corev1.NodeSelectorOpIn, v1.ArchitectureAmd64),
 		scheduling.NewRequirement(corev1.LabelOSStable, corev1.NodeSelectorOpIn, string(corev1.Linux)),
 		scheduling.NewRequirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpIn, v1.CapacityTypeOnDemand),
 		scheduling.NewRequirement(v1alpha1.LabelInstanceCategory, corev1.NodeSelectorOpIn, flavor.Category),
+		scheduling.NewRequirement(v1alpha1.LabelInstanceCPU, corev1.NodeSelectorOpIn, fmt.Sprint(flavor.VCPUs)),
+		scheduling.NewRequirement(v1alpha1.LabelInstanceMemory, corev1.NodeSelectorOpIn, fmt.Sprint(memoryMiB)),
+		scheduling.NewRequirement(v1alpha1.LabelInstanceFamily, corev1.NodeSelectorOpIn, info.Family),
+		scheduling.NewRequirement(v1alpha1.LabelGPUCount, corev1.NodeSelectorOpIn, fmt.Sprint(flavor.GPUs)),
 	)
+	// Size and generation are only set when they can be parsed from the flavor name
+	if info.Size != "" {
+		requirements.Add(scheduling.NewRequirement(v1alpha1.LabelInstanceSize, corev1.NodeSelectorOpIn, info.Size))
+	}
+	if info.Generation != "" {
+		requirements.Add(scheduling.NewRequirement(v1alpha1.LabelInstanceGeneration, corev1.NodeSelectorOpIn, info.Generation))
+	}
+	if nvme, ok := localNVMeDisks[flavor.Name]; ok {
+		requirements.Add(scheduling.NewRequirement(v1alpha1.LabelInstanceLocalNVMe, corev1.NodeSelectorOpIn, fmt.Sprint(nvme)))
+	} else if isIOPSFamily(info.Family) {
+		log.FromContext(ctx).Info("Local NVMe disk count unknown for flavor, not setting the local NVMe label",
+			"flavor", flavor.Name, "label", v1alpha1.LabelInstanceLocalNVMe)
+	}
+	if gpu, ok := lookupGPU(info.Family); ok && flavor.GPUs > 0 {
+		requirements.Add(
+			scheduling.NewRequirement(v1alpha1.LabelGPUName, corev1.NodeSelectorOpIn, gpu.Name),
+			scheduling.NewRequirement(v1alpha1.LabelGPUManufacturer, corev1.NodeSelectorOpIn, gpu.Manufacturer),
+			scheduling.NewRequirement(v1alpha1.LabelGPUMemory, corev1.NodeSelectorOpIn, fmt.Sprint(gpu.MemoryMiB)),
+		)
+	}
 
 	// Build capacity - handle RAM unit conversion
 	var memoryStr string
@@ -793,11 +1234,17 @@ func buildInstanceType(ctx context.Context, flavor ovhclient.Flavor, region stri
 		memoryStr = fmt.Sprintf("%dMi", flavor.RAM)
 	}
 
+	// A zero disk would make every pod with an ephemeral-storage request unschedulable
+	diskGiB := flavor.Disk
+	if diskGiB <= 0 {
+		diskGiB = DefaultFlavorDiskGiB
+	}
+
 	capacity := corev1.ResourceList{
 		corev1.ResourceCPU:              resource.MustParse(fmt.Sprintf("%d", flavor.VCPUs)),
 		corev1.ResourceMemory:           resource.MustParse(memoryStr),
 		corev1.ResourcePods:             resource.MustParse("110"),
-		corev1.ResourceEphemeralStorage: resource.MustParse(fmt.Sprintf("%dGi", flavor.Disk)),
+		corev1.ResourceEphemeralStorage: resource.MustParse(fmt.Sprintf("%dGi", diskGiB)),
 	}
 
 	// Add GPU resources if present
@@ -810,12 +1257,7 @@ func buildInstanceType(ctx context.Context, flavor ovhclient.Flavor, region stri
 		Requirements: requirements,
 		Capacity:     capacity,
 		Offerings:    buildOfferingsWithPricing(ctx, flavor, region, pricingClient),
-		Overhead: &cloudprovider.InstanceTypeOverhead{
-			KubeReserved: corev1.ResourceList{
-				corev1.ResourceCPU:    resource.MustParse("100m"),
-				corev1.ResourceMemory: resource.MustParse("100Mi"),
-			},
-		},
+		Overhead:     computeOverhead(capacity, nil),
 	}
 }
 
@@ -0,0 +1,592 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/lifecycle"
+
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis/v1alpha1"
+	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
+)
+
+const (
+	testServiceName = "project"
+	testKubeID      = "kube"
+	testPoolName    = "karpenter-b2-7-abcdef12"
+)
+
+// fakeMKS serves the node pool endpoints of a single MKS cluster
+type fakeMKS struct {
+	mu    sync.Mutex
+	pools map[string]*ovhclient.NodePool
+	nodes map[string][]ovhclient.Node
+
+	// createPool handles the pool creation request, after the pool was recorded
+	// The created pool is returned when it is not set
+	createPool func(w http.ResponseWriter)
+	// created are the pool creation requests received
+	created []ovhclient.CreateNodePoolRequest
+	// updates are the desired counts requested through pool updates
+	updates []int
+	// quota is the instance quota of the region, quotas cannot be read when nil
+	quota *ovhclient.InstanceQuota
+	// deleted are the IDs of the nodes deleted
+	deleted []string
+}
+
+func newFakeMKS(t *testing.T) (*fakeMKS, *ovhclient.OVHClient) {
+	t.Helper()
+	fake := &fakeMKS{
+		pools: make(map[string]*ovhclient.NodePool),
+		nodes: make(map[string][]ovhclient.Node),
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	ovhClient, err := ovhclient.NewOVHClient(&ovhclient.Credentials{
+		Endpoint:          server.URL,
+		ApplicationKey:    "key",
+		ApplicationSecret: "secret",
+		ConsumerKey:       "consumer",
+	}, testServiceName, testKubeID, "GRA11")
+	if err != nil {
+		t.Fatalf("creating OVH client: %v", err)
+	}
+	return fake, ovhClient.WithRetryConfig(ovhclient.RetryConfig{MaxRetries: 0})
+}
+
+func (f *fakeMKS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	base := fmt.Sprintf("/cloud/project/%s/kube/%s/nodepool", testServiceName, testKubeID)
+	var poolID, nodeID string
+	switch {
+	case r.URL.Path == "/auth/time":
+		fmt.Fprint(w, time.Now().Unix())
+	case r.URL.Path == fmt.Sprintf("/cloud/project/%s/quota", testServiceName) && f.quota != nil:
+		writeJSON(w, []ovhclient.Quota{{Region: "GRA11", Instance: f.quota}})
+	case r.URL.Path == base && r.Method == http.MethodGet:
+		pools := []ovhclient.NodePool{}
+		for _, pool := range f.pools {
+			pools = append(pools, *pool)
+		}
+		writeJSON(w, pools)
+	case r.URL.Path == base && r.Method == http.MethodPost:
+		var req ovhclient.CreateNodePoolRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.created = append(f.created, req)
+		f.pools["pool-1"] = &ovhclient.NodePool{
+			ID:            "pool-1",
+			Name:          req.Name,
+			FlavorName:    req.FlavorName,
+			DesiredNodes:  req.DesiredNodes,
+			MonthlyBilled: req.MonthlyBilled,
+			AntiAffinity:  req.AntiAffinity,
+			Status:        PoolStatusReady,
+			Template:      req.Template,
+		}
+		if f.createPool == nil {
+			writeJSON(w, f.pools["pool-1"])
+			return
+		}
+		f.createPool(w)
+	case matchPath(r.URL.Path, fmt.Sprintf("/cloud/project/%s/kube/%s/node/", testServiceName, testKubeID), "", &nodeID) && r.Method == http.MethodDelete:
+		// Deleting a node lowers the desired count of its pool
+		for id, nodes := range f.nodes {
+			for i, node := range nodes {
+				if node.ID == nodeID {
+					f.nodes[id] = append(nodes[:i:i], nodes[i+1:]...)
+					if pool, ok := f.pools[id]; ok {
+						pool.DesiredNodes--
+					}
+				}
+			}
+		}
+		f.deleted = append(f.deleted, nodeID)
+		writeJSON(w, nil)
+	case matchPath(r.URL.Path, base+"/", "/nodes", &poolID):
+		writeJSON(w, append([]ovhclient.Node{}, f.nodes[poolID]...))
+	case matchPath(r.URL.Path, base+"/", "", &poolID):
+		pool, ok := f.pools[poolID]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(w, map[string]string{"message": "pool not found"})
+			return
+		}
+		if r.Method == http.MethodDelete {
+			pool.Status = PoolStatusDeleting
+			writeJSON(w, nil)
+			return
+		}
+		if r.Method == http.MethodPut {
+			var req ovhclient.UpdateNodePoolRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			pool.DesiredNodes = req.DesiredNodes
+			f.updates = append(f.updates, req.DesiredNodes)
+		}
+		writeJSON(w, pool)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		writeJSON(w, map[string]string{"message": "unexpected request " + r.Method + " " + r.URL.Path})
+	}
+}
+
+// matchPath matches a path made of a prefix, a single ID segment and a suffix
+func matchPath(path, prefix, suffix string, id *string) bool {
+	rest, ok := strings.CutPrefix(path, prefix)
+	if !ok {
+		return false
+	}
+	*id, ok = strings.CutSuffix(rest, suffix)
+	return ok && *id != "" && !strings.Contains(*id, "/")
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestCloudProvider(ovhClient *ovhclient.OVHClient, instanceTypes ...*cloudprovider.InstanceType) *CloudProvider {
+	c := NewCloudProvider(context.Background(), nil, ovhClient, NewInventory(ovhClient, DefaultInventoryRefreshInterval), instanceTypes)
+	// Offerings keep the price they were built with instead of downloading the public catalog
+	c.pricingClient = nil
+	return c
+}
+
+func getOrCreateTestPool(c *CloudProvider) (*ovhclient.NodePool, map[string]bool, error) {
+	metadata := poolMetadata{Flavor: "b2-7", NodeClass: "default", NodePool: "default"}
+	nodeClass := &v1alpha1.OVHNodeClass{}
+	return c.getOrCreatePool(context.Background(), testPoolName, metadata, nodeClass, c.poolTemplate(metadata.Flavor, nodeClass, nil))
+}
+
+// createTestPool creates the pool of a NodeClaim the way launch does
+func createTestPool(c *CloudProvider, flavor string, nodeClass *v1alpha1.OVHNodeClass, nodeClaim *v1.NodeClaim) (*ovhclient.NodePool, error) {
+	template := c.poolTemplate(flavor, nodeClass, nodeClaim)
+	metadata := newPoolMetadata(flavor, "", nodeClass, nodeClaim, template)
+	pool, _, err := c.getOrCreatePool(context.Background(), metadata.PoolName(), metadata, nodeClass, template)
+	return pool, err
+}
+
+func TestGetOrCreatePoolScalesUpPoolCreatedConcurrently(t *testing.T) {
+	fake, ovhClient := newFakeMKS(t)
+	fake.createPool = func(w http.ResponseWriter) {
+		// Another launch created the pool first, and its node is already listed
+		fake.nodes["pool-1"] = []ovhclient.Node{{ID: "node-1", Status: NodeStatusReady}}
+		w.WriteHeader(http.StatusConflict)
+		writeJSON(w, map[string]string{"message": "a node pool with this name already exists"})
+	}
+	c := newTestCloudProvider(ovhClient)
+
+	pool, existingNodeIDs, err := getOrCreateTestPool(c)
+	if err != nil {
+		t.Fatalf("getOrCreatePool: %v", err)
+	}
+	if pool.ID != "pool-1" || pool.DesiredNodes != 2 {
+		t.Errorf("got pool %s with %d desired nodes, want pool-1 with 2", pool.ID, pool.DesiredNodes)
+	}
+	if len(fake.updates) != 1 || fake.updates[0] != 2 {
+		t.Errorf("got desired count updates %v, want [2]", fake.updates)
+	}
+	if len(existingNodeIDs) != 1 || !existingNodeIDs["node-1"] {
+		t.Errorf("got existing nodes %v, want node-1", existingNodeIDs)
+	}
+	if c.poolCache[testPoolName] != "pool-1" {
+		t.Errorf("pool not cached after adoption")
+	}
+}
+
+func TestGetOrCreatePoolAdoptsPoolAfterDroppedConnection(t *testing.T) {
+	fake, ovhClient := newFakeMKS(t)
+	fake.createPool = func(w http.ResponseWriter) {
+		// The pool was created, but the response is lost
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("hijacking connection: %v", err)
+			return
+		}
+		conn.Close()
+	}
+	c := newTestCloudProvider(ovhClient)
+
+	pool, existingNodeIDs, err := getOrCreateTestPool(c)
+	if err != nil {
+		t.Fatalf("getOrCreatePool: %v", err)
+	}
+	if pool.ID != "pool-1" || pool.DesiredNodes != DefaultDesiredNodes {
+		t.Errorf("got pool %s with %d desired nodes, want pool-1 with %d", pool.ID, pool.DesiredNodes, DefaultDesiredNodes)
+	}
+	if len(fake.updates) != 0 {
+		t.Errorf("adopted pool was scaled to %v, the creation already requested the node", fake.updates)
+	}
+	if len(existingNodeIDs) != 0 {
+		t.Errorf("got existing nodes %v, want none", existingNodeIDs)
+	}
+	if c.poolCache[testPoolName] != "pool-1" {
+		t.Errorf("pool not cached after adoption")
+	}
+}
+
+func TestGetOrCreatePoolWritesInstanceLabels(t *testing.T) {
+	tests := []struct {
+		flavor ovhclient.Flavor
+		want   map[string]string
+	}{
+		{
+			flavor: ovhclient.Flavor{Name: "b3-16", Category: "b", VCPUs: 4, RAM: 16},
+			want: map[string]string{
+				corev1.LabelInstanceTypeStable:   "b3-16",
+				v1alpha1.LabelInstanceCategory:   "b",
+				v1alpha1.LabelInstanceCPU:        "4",
+				v1alpha1.LabelInstanceMemory:     "16384",
+				v1alpha1.LabelInstanceFamily:     "b3",
+				v1alpha1.LabelInstanceSize:       "16",
+				v1alpha1.LabelInstanceGeneration: "3",
+				v1alpha1.LabelGPUCount:           "0",
+			},
+		},
+		{
+			flavor: ovhclient.Flavor{Name: "l40s-90", Category: "l", VCPUs: 15, RAM: 90, GPUs: 1},
+			want: map[string]string{
+				corev1.LabelInstanceTypeStable: "l40s-90",
+				v1alpha1.LabelInstanceCPU:      "15",
+				v1alpha1.LabelInstanceMemory:   "92160",
+				v1alpha1.LabelInstanceFamily:   "l40s",
+				v1alpha1.LabelInstanceSize:     "90",
+				v1alpha1.LabelGPUCount:         "1",
+				v1alpha1.LabelGPUName:          "l40s",
+				v1alpha1.LabelGPUManufacturer:  "nvidia",
+				v1alpha1.LabelGPUMemory:        "49152",
+				v1alpha1.LabelPoolNodeClass:    "default",
+				corev1.LabelArchStable:         "amd64",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.flavor.Name, func(t *testing.T) {
+			fake, ovhClient := newFakeMKS(t)
+			instanceType := buildInstanceType(context.Background(), tt.flavor, "GRA11", nil, true)
+			c := newTestCloudProvider(ovhClient, instanceType)
+
+			nodeClass := &v1alpha1.OVHNodeClass{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+			if _, err := createTestPool(c, tt.flavor.Name, nodeClass, nil); err != nil {
+				t.Fatalf("getOrCreatePool: %v", err)
+			}
+			if len(fake.created) != 1 {
+				t.Fatalf("got %d pool creations, want 1", len(fake.created))
+			}
+			labels := fake.created[0].Template.Metadata.Labels
+			for key, want := range tt.want {
+				if got := labels[key]; got != want {
+					t.Errorf("pool template label %s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetOrCreatePoolWritesNodeClaimLabels(t *testing.T) {
+	fake, ovhClient := newFakeMKS(t)
+	c := newTestCloudProvider(ovhClient)
+	nodeClaim := &v1.NodeClaim{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+			v1.NodePoolLabelKey: "default",
+			"team":              "a",
+		}},
+		Spec: v1.NodeClaimSpec{Requirements: []v1.NodeSelectorRequirementWithMinValues{
+			{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: "env", Operator: corev1.NodeSelectorOpIn, Values: []string{"prod"}}},
+			{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: "tier", Operator: corev1.NodeSelectorOpIn, Values: []string{"web", "api"}}},
+		}},
+	}
+
+	nodeClass := &v1alpha1.OVHNodeClass{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	if _, err := createTestPool(c, "b3-8", nodeClass, nodeClaim); err != nil {
+		t.Fatalf("getOrCreatePool: %v", err)
+	}
+	labels := fake.created[0].Template.Metadata.Labels
+	for key, want := range map[string]string{"team": "a", "env": "prod", v1.NodePoolLabelKey: "default"} {
+		if got := labels[key]; got != want {
+			t.Errorf("pool template label %s = %q, want %q", key, got, want)
+		}
+	}
+	if _, ok := labels["tier"]; ok {
+		t.Errorf("multi-value requirement tier written into the pool template")
+	}
+}
+
+func TestIsDriftedAfterNodeClaimIsPopulated(t *testing.T) {
+	tests := []struct {
+		name string
+		// edit changes the NodeClass or the pool after the launch
+		edit func(nodeClass *v1alpha1.OVHNodeClass, pool *ovhclient.NodePool)
+		// annotations are added to the NodeClaim after the launch
+		annotations map[string]string
+		want        cloudprovider.DriftReason
+	}{
+		{
+			name: "unchanged",
+			edit: func(*v1alpha1.OVHNodeClass, *ovhclient.NodePool) {},
+		},
+		{
+			name: "pool template label edited",
+			edit: func(_ *v1alpha1.OVHNodeClass, pool *ovhclient.NodePool) {
+				pool.Template.Metadata.Labels["team"] = "b"
+			},
+			want: "PoolTemplateDrifted",
+		},
+		{
+			name: "pool template taint added",
+			edit: func(_ *v1alpha1.OVHNodeClass, pool *ovhclient.NodePool) {
+				pool.Template.Spec.Taints = append(pool.Template.Spec.Taints, corev1.Taint{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule})
+			},
+			want: "PoolTemplateDrifted",
+		},
+		{
+			name: "NodeClass tags changed",
+			edit: func(nodeClass *v1alpha1.OVHNodeClass, _ *ovhclient.NodePool) {
+				nodeClass.Spec.Tags = map[string]string{"cost-center": "b"}
+			},
+			want: "NodeClassDrifted",
+		},
+		{
+			name:        "marked for interruption",
+			edit:        func(*v1alpha1.OVHNodeClass, *ovhclient.NodePool) {},
+			annotations: map[string]string{v1alpha1.AnnotationInterruption: "maintenance"},
+			want:        "Interrupted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fake, ovhClient := newFakeMKS(t)
+			nodeClass := &v1alpha1.OVHNodeClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Spec:       v1alpha1.OVHNodeClassSpec{Tags: map[string]string{"cost-center": "a"}},
+			}
+			nodeClaim := &v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "default-abcde", Labels: map[string]string{v1.NodePoolLabelKey: "default"}},
+				Spec: v1.NodeClaimSpec{
+					NodeClassRef: &v1.NodeClassReference{Name: "default"},
+					Requirements: []v1.NodeSelectorRequirementWithMinValues{
+						{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: "team", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}},
+						{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: "tier", Operator: corev1.NodeSelectorOpIn, Values: []string{"web", "api"}}},
+						{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: corev1.LabelInstanceTypeStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"b3-8"}}},
+					},
+					Taints: []corev1.Taint{{Key: "workload", Value: "batch", Effect: corev1.TaintEffectNoSchedule}},
+				},
+			}
+			c := newTestCloudProvider(ovhClient)
+			pool, err := createTestPool(c, "b3-8", nodeClass, nodeClaim)
+			if err != nil {
+				t.Fatalf("getOrCreatePool: %v", err)
+			}
+
+			// Karpenter adds the requirement labels to the NodeClaim once launched
+			nodeClaim = lifecycle.PopulateNodeClaimDetails(nodeClaim, &v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{v1alpha1.AnnotationOVHPoolID: pool.ID}},
+			})
+			tt.edit(nodeClass, fake.pools[pool.ID])
+			for key, value := range tt.annotations {
+				nodeClaim.Annotations[key] = value
+			}
+			c.kubeClient = fakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(nodeClass).Build()
+
+			got, err := c.IsDrifted(ctx, nodeClaim)
+			if err != nil {
+				t.Fatalf("IsDrifted: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsDrifted = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAbandonLaunch(t *testing.T) {
+	tests := []struct {
+		name string
+		// poolStatus is the status of the pool when the launch scaled it up
+		poolStatus string
+		claimed    *ovhclient.Node
+		// appears is a node listed after the launch was abandoned
+		appears *ovhclient.Node
+		// waiting is the number of other launches waiting on the pool
+		waiting int
+		// wantDesired is the desired count of the pool once the abandoned launches are reaped
+		wantDesired int
+		wantDeleted []string
+		// wantAbandoned is the number of abandoned launches still waiting for their node
+		wantAbandoned int
+	}{
+		{
+			name:        "claimed node is deleted",
+			poolStatus:  PoolStatusReady,
+			claimed:     &ovhclient.Node{ID: "node-2", Name: "node-2", Status: NodeStatusError},
+			wantDesired: 1,
+			wantDeleted: []string{"node-2"},
+		},
+		{
+			name:        "queued scale up is withdrawn",
+			poolStatus:  PoolStatusUpdating,
+			wantDesired: 1,
+		},
+		{
+			name:          "applied scale up is kept until the node appears",
+			poolStatus:    PoolStatusReady,
+			wantDesired:   2,
+			wantAbandoned: 1,
+		},
+		{
+			name:        "node appearing after the launch is deleted",
+			poolStatus:  PoolStatusReady,
+			appears:     &ovhclient.Node{ID: "node-2", Name: "node-2"},
+			wantDesired: 1,
+			wantDeleted: []string{"node-2"},
+		},
+		{
+			name:          "node appearing for a waiting launch is left to it",
+			poolStatus:    PoolStatusReady,
+			appears:       &ovhclient.Node{ID: "node-2", Name: "node-2"},
+			waiting:       1,
+			wantDesired:   2,
+			wantAbandoned: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fake, ovhClient := newFakeMKS(t)
+			fake.pools["pool-1"] = &ovhclient.NodePool{ID: "pool-1", Name: testPoolName, DesiredNodes: 1, Status: tt.poolStatus}
+			fake.nodes["pool-1"] = []ovhclient.Node{{ID: "node-1", Status: NodeStatusReady}}
+			if tt.claimed != nil {
+				fake.nodes["pool-1"] = append(fake.nodes["pool-1"], *tt.claimed)
+			}
+			c := newTestCloudProvider(ovhClient)
+
+			if _, err := c.scaler.Scale(ctx, "pool-1", 1); err != nil {
+				t.Fatalf("scaling up: %v", err)
+			}
+			c.abandonLaunch(ctx, "pool-1", map[string]bool{"node-1": true}, tt.claimed)
+			if _, err := c.scaler.Flush(ctx, "pool-1"); err != nil {
+				t.Fatalf("flushing: %v", err)
+			}
+
+			if tt.appears != nil {
+				fake.mu.Lock()
+				fake.nodes["pool-1"] = append(fake.nodes["pool-1"], *tt.appears)
+				fake.mu.Unlock()
+			}
+			for range tt.waiting {
+				c.startWaiting("pool-1")
+			}
+			c.reapAbandonedLaunches(ctx)
+
+			if got := fake.pools["pool-1"].DesiredNodes; got != tt.wantDesired {
+				t.Errorf("got %d desired nodes, want %d", got, tt.wantDesired)
+			}
+			if fmt.Sprint(fake.deleted) != fmt.Sprint(tt.wantDeleted) {
+				t.Errorf("got deleted nodes %v, want %v", fake.deleted, tt.wantDeleted)
+			}
+			if c.scaler.Pending("pool-1") != 0 {
+				t.Errorf("got %d desired nodes still queued, want none", c.scaler.Pending("pool-1"))
+			}
+			if got := len(c.abandonedLaunches["pool-1"]); got != tt.wantAbandoned {
+				t.Errorf("got %d abandoned launches, want %d", got, tt.wantAbandoned)
+			}
+		})
+	}
+}
+
+func TestDeletePoolOfLastNodeRemovesPoolFromInventory(t *testing.T) {
+	ctx := context.Background()
+	fake, ovhClient := newFakeMKS(t)
+	pool := ovhclient.NodePool{ID: "pool-1", Name: testPoolName, DesiredNodes: 1, Status: PoolStatusReady}
+	node := ovhclient.Node{ID: "node-1", InstanceID: "instance-1", Status: NodeStatusReady}
+	fake.pools["pool-1"] = &pool
+	fake.nodes["pool-1"] = []ovhclient.Node{node}
+	c := newTestCloudProvider(ovhClient)
+	c.inventory.PutPool(pool)
+	c.inventory.PutNode("pool-1", node)
+
+	deleted, err := c.deletePoolOfLastNode(ctx, "pool-1", []ovhclient.Node{node})
+	if err != nil || !deleted {
+		t.Fatalf("deletePoolOfLastNode = %t, %v, want the pool deleted", deleted, err)
+	}
+	if fake.pools["pool-1"].Status != PoolStatusDeleting {
+		t.Errorf("pool not deleted in MKS")
+	}
+	if _, _, ok := c.inventory.lookupInstance("instance-1"); ok {
+		t.Errorf("node of the deleted pool still in the inventory")
+	}
+	if nodes := c.inventory.Nodes("pool-1"); len(nodes) != 0 {
+		t.Errorf("got %d nodes of the deleted pool in the inventory, want none", len(nodes))
+	}
+}
+
+func TestGetInstanceTypesPricesOfferingsFromCurrentCatalog(t *testing.T) {
+	ctx := context.Background()
+	_, ovhClient := newFakeMKS(t)
+	catalog := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, ovhclient.PricingCatalog{Addons: []ovhclient.PricingAddon{{
+			PlanCode: "instance-b3-8.consumption",
+			Pricings: []ovhclient.PricingDetail{{Capacities: []string{"consumption"}, Duration: "P1H", Price: 5000000}},
+		}}})
+	}))
+	t.Cleanup(catalog.Close)
+	pricingClient := ovhclient.NewPricingClient("FR").WithBaseURL(catalog.URL)
+
+	// Instance types are built before the catalog is loaded, with estimated prices
+	priced := buildInstanceType(ctx, ovhclient.Flavor{Name: "b3-8", Category: "b", VCPUs: 2, RAM: 8}, "GRA11", nil, true)
+	unpriced := buildInstanceType(ctx, ovhclient.Flavor{Name: "x9-8", Category: "x", VCPUs: 2, RAM: 8}, "GRA11", nil, true)
+	c := NewCloudProviderWithPricing(ctx, nil, ovhClient, pricingClient, NewInventory(ovhClient, DefaultInventoryRefreshInterval),
+		[]*cloudprovider.InstanceType{priced, unpriced})
+	if err := pricingClient.Refresh(ctx); err != nil {
+		t.Fatalf("loading catalog: %v", err)
+	}
+
+	instanceTypes, err := c.GetInstanceTypes(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetInstanceTypes: %v", err)
+	}
+	want := map[string]float64{"b3-8": 0.05, "x9-8": unpriced.Offerings[0].Price}
+	for _, it := range instanceTypes {
+		for _, offering := range it.Offerings {
+			if offering.Price != want[it.Name] {
+				t.Errorf("%s offering priced %v, want %v", it.Name, offering.Price, want[it.Name])
+			}
+		}
+	}
+	if priced.Offerings[0].Price == 0.05 {
+		t.Errorf("offerings built at startup were modified")
+	}
+}
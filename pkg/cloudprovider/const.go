@@ -16,6 +16,8 @@ limitations under the License.
 
 package ovhcloud
 
+import "time"
+
 const (
 	// ProviderPrefix is the prefix for OVH provider IDs
 	// Format: openstack:///{instanceId} - matches what OVH MKS sets on nodes
@@ -26,4 +28,70 @@ const (
 
 	// DefaultDesiredNodes is the default number of nodes for a new pool
 	DefaultDesiredNodes = 1
+
+	// DefaultFlavorDiskGiB is the root disk size assumed when the flavor API does not report one
+	// This is the smallest root disk offered on MKS flavors
+	DefaultFlavorDiskGiB = 25
+
+	// DefaultPoolCollectorInterval is how often the pool collector snapshots the node pools
+	DefaultPoolCollectorInterval = time.Minute
+
+	// DefaultInventoryRefreshInterval is how often the pool inventory read by Get and List is refreshed
+	DefaultInventoryRefreshInterval = 30 * time.Second
+
+	// DefaultUnavailableOfferingsTTL is how long a flavor and zone pair is skipped after a failed launch
+	DefaultUnavailableOfferingsTTL = 3 * time.Minute
+
+	// DefaultCostCollectorInterval is how often the cost collector prices the running nodes
+	DefaultCostCollectorInterval = time.Minute
+
+	// DefaultLaunchTimeout is how long a launched node may take to become READY when the NodeClass does not set it
+	DefaultLaunchTimeout = 10 * time.Minute
+
+	// DefaultLaunchReaperInterval is how often the nodes of abandoned launches are looked for
+	DefaultLaunchReaperInterval = 30 * time.Second
+
+	// AbandonedLaunchTTL is how long the node of an abandoned launch is waited for before it is left to garbage collection
+	AbandonedLaunchTTL = time.Hour
+
+	// DefaultPoolStuckThreshold is how long a pool may stay in a transitional status before it is reported as stuck
+	DefaultPoolStuckThreshold = 15 * time.Minute
 )
+
+// MKS node pool statuses
+const (
+	PoolStatusReady       = "READY"
+	PoolStatusInstalling  = "INSTALLING"
+	PoolStatusUpdating    = "UPDATING"
+	PoolStatusResizing    = "RESIZING"
+	PoolStatusRedeploying = "REDEPLOYING"
+	PoolStatusDeleting    = "DELETING"
+	PoolStatusError       = "ERROR"
+)
+
+// MKS node statuses
+const (
+	NodeStatusReady          = "READY"
+	NodeStatusError          = "ERROR"
+	NodeStatusDeleting       = "DELETING"
+	NodeStatusUserError      = "USER_ERROR"
+	NodeStatusUserQuotaError = "USER_QUOTA_ERROR"
+)
+
+// isFailedNodeStatus returns true for statuses a launching node never recovers from
+func isFailedNodeStatus(status string) bool {
+	switch status {
+	case NodeStatusError, NodeStatusUserError, NodeStatusUserQuotaError:
+		return true
+	}
+	return false
+}
+
+// isTransitionalPoolStatus returns true for statuses MKS moves out of on its own
+func isTransitionalPoolStatus(status string) bool {
+	switch status {
+	case PoolStatusInstalling, PoolStatusUpdating, PoolStatusResizing, PoolStatusRedeploying, PoolStatusDeleting:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis/v1alpha1"
+	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+)
+
+const (
+	billingHourly  = "hourly"
+	billingMonthly = "monthly"
+)
+
+// costKey groups running nodes for the cost metrics
+type costKey struct {
+	NodePool string
+	Flavor   string
+	Zone     string
+	Billing  string
+}
+
+// CostCollector periodically prices the Karpenter-managed nodes returned by List
+// and exports the estimated hourly spend and accumulated node-hours
+type CostCollector struct {
+	cloudProvider *CloudProvider
+	interval      time.Duration
+
+	// Groups whose cost gauge was set by the last snapshot
+	recorded map[costKey]bool
+}
+
+// NewCostCollector creates a new cost collector
+func NewCostCollector(cloudProvider *CloudProvider, interval time.Duration) *CostCollector {
+	return &CostCollector{
+		cloudProvider: cloudProvider,
+		interval:      interval,
+		recorded:      make(map[costKey]bool),
+	}
+}
+
+// Start records the cost metrics every interval until the context is cancelled
+func (c *CostCollector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	c.record(ctx, 0)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			// Node-hours of failed snapshots are accumulated by the next successful one
+			if c.record(ctx, now.Sub(last)) {
+				last = now
+			}
+		}
+	}
+}
+
+// NeedLeaderElection ensures only the leader polls the OVH API and accumulates node-hours
+func (c *CostCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// record snapshots the running nodes and accumulates node-hours for the elapsed duration
+// It returns false when the nodes could not be listed, leaving the metrics untouched
+func (c *CostCollector) record(ctx context.Context, elapsed time.Duration) bool {
+	nodeClaims, err := c.cloudProvider.List(ctx)
+	if err != nil {
+		log.FromContext(ctx).V(1).Info("Failed to list nodes for cost metrics", "error", err)
+		return false
+	}
+
+	costs := make(map[costKey]float64)
+	for _, nodeClaim := range nodeClaims {
+		key := costKey{
+			NodePool: nodeClaim.Labels[v1.NodePoolLabelKey],
+			Flavor:   nodeClaim.Labels[corev1.LabelInstanceTypeStable],
+			Zone:     nodeClaim.Labels[corev1.LabelTopologyZone],
+			Billing:  billingHourly,
+		}
+		if nodeClaim.Annotations[v1alpha1.AnnotationOVHMonthlyBilled] == "true" {
+			key.Billing = billingMonthly
+		}
+		costs[key] += c.hourlyPrice(ctx, key)
+
+		if elapsed > 0 {
+			RecordNodeHours(key.NodePool, key.Flavor, key.Zone, key.Billing, elapsed.Hours())
+		}
+	}
+
+	// Update the gauges in place and only delete the groups that are gone, so that scrapes
+	// never see the series of running nodes disappear
+	for key, cost := range costs {
+		SetEstimatedHourlyCost(key.NodePool, key.Flavor, key.Zone, key.Billing, cost)
+	}
+	for key := range c.recorded {
+		if _, ok := costs[key]; !ok {
+			DeleteEstimatedHourlyCost(key.NodePool, key.Flavor, key.Zone, key.Billing)
+			delete(c.recorded, key)
+		}
+	}
+	for key := range costs {
+		c.recorded[key] = true
+	}
+	return true
+}
+
+// hourlyPrice returns the hourly price of a node, spreading monthly prices over the hours of a month
+func (c *CostCollector) hourlyPrice(ctx context.Context, key costKey) float64 {
+	pricingClient := c.cloudProvider.pricingClient
+	if pricingClient == nil {
+		return 0
+	}
+	if key.Billing == billingMonthly {
+		if price, ok := pricingClient.GetFlavorMonthlyPrice(ctx, key.Flavor); ok {
+			return price / ovhclient.HoursPerMonth
+		}
+	}
+	price, err := pricingClient.GetFlavorPrice(ctx, key.Flavor, c.cloudProvider.ovhClient.GetRegion())
+	if err != nil {
+		return 0
+	}
+	return price
+}
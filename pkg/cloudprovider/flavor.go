@@ -0,0 +1,113 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"regexp"
+	"strings"
+)
+
+// generationalFamilyPattern matches families whose digits are a hardware generation
+// (b3, c2, r3, d2, i1, t1, t2), as opposed to GPU model families such as a100 or l40s
+var generationalFamilyPattern = regexp.MustCompile(`^[bcdirt]([0-9]+)$`)
+
+// gpuInfo describes the GPU model fitted to an OVH flavor family
+type gpuInfo struct {
+	Name         string
+	Manufacturer string
+	MemoryMiB    int // per GPU
+}
+
+// gpuFamilies maps the first segment of an OVH GPU flavor family to its GPU model
+// Low-energy variants (t1-le, t2-le) share the model of their base family
+var gpuFamilies = map[string]gpuInfo{
+	"t1":      {Name: "v100", Manufacturer: "nvidia", MemoryMiB: 16 * 1024},
+	"t2":      {Name: "v100s", Manufacturer: "nvidia", MemoryMiB: 32 * 1024},
+	"a10":     {Name: "a10", Manufacturer: "nvidia", MemoryMiB: 24 * 1024},
+	"a100":    {Name: "a100", Manufacturer: "nvidia", MemoryMiB: 80 * 1024},
+	"l4":      {Name: "l4", Manufacturer: "nvidia", MemoryMiB: 24 * 1024},
+	"l40s":    {Name: "l40s", Manufacturer: "nvidia", MemoryMiB: 48 * 1024},
+	"h100":    {Name: "h100", Manufacturer: "nvidia", MemoryMiB: 80 * 1024},
+	"rtx5000": {Name: "rtx5000", Manufacturer: "nvidia", MemoryMiB: 16 * 1024},
+}
+
+// lookupGPU returns the GPU model for a flavor family, if it is a known GPU family
+func lookupGPU(family string) (gpuInfo, bool) {
+	base, _, _ := strings.Cut(family, "-")
+	gpu, ok := gpuFamilies[base]
+	return gpu, ok
+}
+
+// localNVMeDisks is the number of local NVMe data disks on IOPS-optimized flavors
+// These are raw disks in addition to the root disk and are not part of ephemeral storage.
+// Neither the capabilities nor the project flavor API report data disks (isLocalStorage only
+// describes the root disk), so they are listed here from the published flavor specifications.
+// IOPS flavors missing from this table are logged when instance types are built and get no
+// local NVMe label until they are added.
+var localNVMeDisks = map[string]int{
+	"i1-45":  2,
+	"i1-90":  4,
+	"i1-180": 4,
+}
+
+// isIOPSFamily returns true for the IOPS-optimized families (i1, ...), which have local NVMe data disks
+func isIOPSFamily(family string) bool {
+	m := generationalFamilyPattern.FindStringSubmatch(family)
+	return m != nil && strings.HasPrefix(family, "i")
+}
+
+// flavorInfo holds the components parsed out of an OVH flavor name
+type flavorInfo struct {
+	Family     string
+	Size       string
+	Generation string
+}
+
+// parseFlavorName splits an OVH flavor name into family, size and generation
+// OVH naming: {family}-{size}[-{suffix}] e.g., b3-16, t1-le-45, b2-7-flex
+// The size is the last purely numeric segment; everything before it is the family
+func parseFlavorName(name string) flavorInfo {
+	parts := strings.Split(strings.ToLower(name), "-")
+
+	info := flavorInfo{Family: strings.ToLower(name)}
+	for i := len(parts) - 1; i > 0; i-- {
+		if isNumeric(parts[i]) {
+			info.Family = strings.Join(parts[:i], "-")
+			info.Size = parts[i]
+			break
+		}
+	}
+
+	// Generation is only meaningful for the first segment (e.g., "t1" in "t1-le")
+	if m := generationalFamilyPattern.FindStringSubmatch(parts[0]); m != nil {
+		info.Generation = m[1]
+	}
+
+	return info
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
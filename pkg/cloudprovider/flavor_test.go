@@ -0,0 +1,64 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import "testing"
+
+func TestIsIOPSFamily(t *testing.T) {
+	tests := []struct {
+		family string
+		want   bool
+	}{
+		{family: "i1", want: true},
+		{family: "i2", want: true},
+		{family: "b3", want: false},
+		{family: "t1-le", want: false},
+		{family: "l40s", want: false},
+		{family: "iops", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.family, func(t *testing.T) {
+			if got := isIOPSFamily(tt.family); got != tt.want {
+				t.Errorf("isIOPSFamily(%q) = %t, want %t", tt.family, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFlavorName(t *testing.T) {
+	tests := []struct {
+		name string
+		want flavorInfo
+	}{
+		{name: "b3-16", want: flavorInfo{Family: "b3", Size: "16", Generation: "3"}},
+		{name: "B2-7", want: flavorInfo{Family: "b2", Size: "7", Generation: "2"}},
+		{name: "b2-7-flex", want: flavorInfo{Family: "b2", Size: "7", Generation: "2"}},
+		{name: "t1-le-45", want: flavorInfo{Family: "t1-le", Size: "45", Generation: "1"}},
+		{name: "i1-180", want: flavorInfo{Family: "i1", Size: "180", Generation: "1"}},
+		{name: "l40s-90", want: flavorInfo{Family: "l40s", Size: "90"}},
+		{name: "d2-2", want: flavorInfo{Family: "d2", Size: "2", Generation: "2"}},
+		{name: "win-b2-15", want: flavorInfo{Family: "win-b2", Size: "15"}},
+		{name: "custom", want: flavorInfo{Family: "custom"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseFlavorName(tt.name); got != tt.want {
+				t.Errorf("parseFlavorName(%q) = %+v, want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
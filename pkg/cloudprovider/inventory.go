@@ -0,0 +1,332 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
+)
+
+// inventoryNode locates a node in the inventory
+type inventoryNode struct {
+	PoolID string
+	Node   ovhclient.Node
+}
+
+// Inventory is a periodically refreshed snapshot of the Karpenter-managed pools and their nodes
+// Get and List read from it instead of listing every pool's nodes on each call.
+// Create and Delete write through it so that the snapshot reflects their changes immediately.
+type Inventory struct {
+	ovhClient *ovhclient.OVHClient
+	interval  time.Duration
+
+	// refreshMu serializes snapshots so concurrent readers trigger a single refresh
+	refreshMu sync.Mutex
+
+	mu        sync.RWMutex
+	lastSync  time.Time
+	pools     map[string]ovhclient.NodePool // pool ID -> pool
+	nodes     map[string][]ovhclient.Node   // pool ID -> nodes
+	instances map[string]inventoryNode      // instance ID -> node
+
+	// Write-throughs made while a refresh lists the OVH API, replayed on top of its snapshot
+	// as the listing may predate them
+	refreshing bool
+	writes     []func()
+}
+
+// NewInventory creates a new inventory refreshed every interval
+func NewInventory(ovhClient *ovhclient.OVHClient, interval time.Duration) *Inventory {
+	return &Inventory{
+		ovhClient: ovhClient,
+		interval:  interval,
+		pools:     make(map[string]ovhclient.NodePool),
+		nodes:     make(map[string][]ovhclient.Node),
+		instances: make(map[string]inventoryNode),
+	}
+}
+
+// Start refreshes the inventory every interval until the context is cancelled
+func (i *Inventory) Start(ctx context.Context) error {
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := i.Refresh(ctx); err != nil {
+			log.FromContext(ctx).V(1).Info("Failed to refresh pool inventory", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection ensures only the leader polls the OVH API
+// Replicas that are not leading refresh the inventory on demand
+func (i *Inventory) NeedLeaderElection() bool {
+	return true
+}
+
+// Refresh lists the Karpenter-managed pools and their nodes and replaces the snapshot
+// Pools whose nodes cannot be listed keep the nodes of the previous snapshot
+func (i *Inventory) Refresh(ctx context.Context) error {
+	i.refreshMu.Lock()
+	defer i.refreshMu.Unlock()
+	return i.refreshLocked(ctx)
+}
+
+func (i *Inventory) refreshLocked(ctx context.Context) error {
+	i.mu.Lock()
+	i.refreshing = true
+	i.mu.Unlock()
+	defer func() {
+		i.mu.Lock()
+		i.refreshing = false
+		i.writes = nil
+		i.mu.Unlock()
+	}()
+
+	allPools, err := i.ovhClient.ListNodePools(ctx)
+	if err != nil {
+		return fmt.Errorf("listing pools: %w", err)
+	}
+
+	pools := make(map[string]ovhclient.NodePool)
+	nodes := make(map[string][]ovhclient.Node)
+	for _, pool := range allPools {
+		if !strings.HasPrefix(pool.Name, PoolNamePrefix) {
+			continue
+		}
+		pools[pool.ID] = pool
+
+		poolNodes, err := i.ovhClient.ListPoolNodes(ctx, pool.ID)
+		if err != nil {
+			log.FromContext(ctx).V(1).Info("Failed to list pool nodes, keeping previous snapshot", "poolID", pool.ID, "error", err)
+			i.mu.RLock()
+			poolNodes = i.nodes[pool.ID]
+			i.mu.RUnlock()
+		}
+		nodes[pool.ID] = poolNodes
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.pools = pools
+	i.nodes = nodes
+	i.reindex()
+	for _, write := range i.writes {
+		write()
+	}
+	i.lastSync = time.Now()
+	return nil
+}
+
+// reindex rebuilds the instance ID index; callers must hold the write lock
+func (i *Inventory) reindex() {
+	i.instances = make(map[string]inventoryNode)
+	for poolID, poolNodes := range i.nodes {
+		for _, node := range poolNodes {
+			if node.InstanceID != "" {
+				i.instances[node.InstanceID] = inventoryNode{PoolID: poolID, Node: node}
+			}
+		}
+	}
+}
+
+// ensureFresh refreshes the snapshot synchronously when it was never taken or is older than maxAge
+func (i *Inventory) ensureFresh(ctx context.Context, maxAge time.Duration) error {
+	i.mu.RLock()
+	fresh := !i.lastSync.IsZero() && time.Since(i.lastSync) <= maxAge
+	i.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	i.refreshMu.Lock()
+	defer i.refreshMu.Unlock()
+
+	// Double-check, another caller may have refreshed while we waited
+	i.mu.RLock()
+	fresh = !i.lastSync.IsZero() && time.Since(i.lastSync) <= maxAge
+	i.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+	return i.refreshLocked(ctx)
+}
+
+// synced returns true once a snapshot was taken
+func (i *Inventory) synced() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return !i.lastSync.IsZero()
+}
+
+// Pools returns the Karpenter-managed pools, sorted by name
+// When the OVH API is unavailable, the last snapshot is served
+func (i *Inventory) Pools(ctx context.Context) ([]ovhclient.NodePool, error) {
+	if err := i.ensureFresh(ctx, 2*i.interval); err != nil {
+		if !i.synced() {
+			return nil, err
+		}
+		log.FromContext(ctx).V(1).Info("Serving last known pool inventory", "error", err)
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	pools := make([]ovhclient.NodePool, 0, len(i.pools))
+	for _, pool := range i.pools {
+		pools = append(pools, pool)
+	}
+	sort.Slice(pools, func(a, b int) bool { return pools[a].Name < pools[b].Name })
+	return pools, nil
+}
+
+// Nodes returns the nodes of a pool
+func (i *Inventory) Nodes(poolID string) []ovhclient.Node {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return append([]ovhclient.Node(nil), i.nodes[poolID]...)
+}
+
+// FindInstance returns the node with the given instance ID and its pool, or a nil node if it does not exist
+// A miss triggers a refresh when the snapshot is older than a refresh interval,
+// so that nodes created since the last refresh are found. When the OVH API is unavailable,
+// hits are served from the last snapshot and misses return an error, as absence cannot be confirmed.
+func (i *Inventory) FindInstance(ctx context.Context, instanceID string) (*ovhclient.Node, *ovhclient.NodePool, error) {
+	if err := i.ensureFresh(ctx, 2*i.interval); err != nil && !i.synced() {
+		return nil, nil, err
+	}
+	if node, pool, ok := i.lookupInstance(instanceID); ok {
+		return node, pool, nil
+	}
+
+	if err := i.ensureFresh(ctx, i.interval); err != nil {
+		return nil, nil, err
+	}
+	if node, pool, ok := i.lookupInstance(instanceID); ok {
+		return node, pool, nil
+	}
+	return nil, nil, nil
+}
+
+func (i *Inventory) lookupInstance(instanceID string) (*ovhclient.Node, *ovhclient.NodePool, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	entry, ok := i.instances[instanceID]
+	if !ok {
+		return nil, nil, false
+	}
+	pool, ok := i.pools[entry.PoolID]
+	if !ok {
+		return nil, nil, false
+	}
+	node := entry.Node
+	return &node, &pool, true
+}
+
+// write applies a write-through to the snapshot, and records it for the refresh in progress
+// Callers must hold the write lock
+func (i *Inventory) write(apply func()) {
+	apply()
+	if i.refreshing {
+		i.writes = append(i.writes, apply)
+	}
+}
+
+// PutPool records a created or updated pool
+func (i *Inventory) PutPool(pool ovhclient.NodePool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.write(func() { i.pools[pool.ID] = pool })
+}
+
+// PutNode records a created node, replacing any node with the same ID
+func (i *Inventory) PutNode(poolID string, node ovhclient.Node) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.write(func() { i.putNode(poolID, node) })
+}
+
+func (i *Inventory) putNode(poolID string, node ovhclient.Node) {
+	poolNodes := i.nodes[poolID]
+	for idx := range poolNodes {
+		if poolNodes[idx].ID == node.ID {
+			poolNodes[idx] = node
+			i.reindex()
+			return
+		}
+	}
+	i.nodes[poolID] = append(poolNodes, node)
+	if node.InstanceID != "" {
+		i.instances[node.InstanceID] = inventoryNode{PoolID: poolID, Node: node}
+	}
+}
+
+// RemoveNode forgets a deleted node
+func (i *Inventory) RemoveNode(poolID, nodeID string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.write(func() { i.removeNode(poolID, nodeID) })
+}
+
+func (i *Inventory) removeNode(poolID, nodeID string) {
+	var remaining []ovhclient.Node
+	removed := false
+	for _, node := range i.nodes[poolID] {
+		if node.ID == nodeID {
+			delete(i.instances, node.InstanceID)
+			removed = true
+			continue
+		}
+		remaining = append(remaining, node)
+	}
+	if !removed {
+		return
+	}
+	i.nodes[poolID] = remaining
+	if pool, ok := i.pools[poolID]; ok && pool.CurrentNodes > 0 {
+		pool.CurrentNodes--
+		pool.DesiredNodes = max(pool.DesiredNodes-1, 0)
+		i.pools[poolID] = pool
+	}
+}
+
+// RemovePool forgets a deleted pool and its nodes
+func (i *Inventory) RemovePool(poolID string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.write(func() { i.removePool(poolID) })
+}
+
+func (i *Inventory) removePool(poolID string) {
+	for _, node := range i.nodes[poolID] {
+		delete(i.instances, node.InstanceID)
+	}
+	delete(i.nodes, poolID)
+	delete(i.pools, poolID)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+)
+
+// maxLaunchCandidates bounds the offerings tried by a single Create call
+const maxLaunchCandidates = 5
+
+// errNodeLaunchFailed is returned when the node of a launch went into a failed status or timed out
+var errNodeLaunchFailed = errors.New("node failed to launch")
+
+// launchCandidate is a flavor and zone a NodeClaim can be launched with
+type launchCandidate struct {
+	InstanceType *cloudprovider.InstanceType
+	Zone         string
+	Price        float64
+}
+
+// launchCandidates returns the viable offerings for a NodeClaim, cheapest first
+// An offering is viable when its instance type and zone satisfy the NodeClaim requirements
+// (In, NotIn, Exists, ... on any label including the zone), it is available, it did not
+// recently fail to launch and the instance fits in the project quota. Zones of the same flavor
+// are ordered by the number of nodes the NodeClaim's NodePool already has in them, so that
+// unconstrained NodeClaims are spread across zones.
+func (c *CloudProvider) launchCandidates(ctx context.Context, nodeClaim *v1.NodeClaim, instanceTypes []*cloudprovider.InstanceType) []launchCandidate {
+	logger := log.FromContext(ctx)
+	reqs := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...)
+
+	// Quota headroom is best effort, launches are not blocked when quotas cannot be read
+	quota, err := c.ovhClient.GetInstanceQuota(ctx, c.ovhClient.GetRegion())
+	if err != nil {
+		logger.V(1).Info("Cannot read instance quota, skipping quota headroom check", "error", err)
+	}
+
+	var candidates []launchCandidate
+	for _, it := range instanceTypes {
+		if reqs.Compatible(it.Requirements, scheduling.AllowUndefinedWellKnownLabels) != nil {
+			continue
+		}
+		if quota != nil && !quotaFits(quota, it) {
+			logger.V(1).Info("Skipping instance type exceeding quota headroom", "flavor", it.Name)
+			continue
+		}
+		for _, offering := range it.Offerings.Available().Compatible(reqs) {
+			zone := offering.Requirements.Get(corev1.LabelTopologyZone).Any()
+			if c.unavailableOfferings.IsUnavailable(it.Name, zone) {
+				continue
+			}
+			candidates = append(candidates, launchCandidate{InstanceType: it, Zone: zone, Price: offering.Price})
+		}
+	}
+
+	zoneNodes := c.zoneNodeCounts(ctx, nodeClaim.Labels[v1.NodePoolLabelKey])
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Price != candidates[j].Price {
+			return candidates[i].Price < candidates[j].Price
+		}
+		if candidates[i].InstanceType.Name != candidates[j].InstanceType.Name {
+			return candidates[i].InstanceType.Name < candidates[j].InstanceType.Name
+		}
+		if zoneNodes[candidates[i].Zone] != zoneNodes[candidates[j].Zone] {
+			return zoneNodes[candidates[i].Zone] < zoneNodes[candidates[j].Zone]
+		}
+		return candidates[i].Zone < candidates[j].Zone
+	})
+	if len(candidates) > maxLaunchCandidates {
+		candidates = candidates[:maxLaunchCandidates]
+	}
+	return candidates
+}
+
+// zoneNodeCounts returns the number of launched NodeClaims of a NodePool per zone
+func (c *CloudProvider) zoneNodeCounts(ctx context.Context, nodePool string) map[string]int {
+	counts := make(map[string]int)
+	if nodePool == "" {
+		return counts
+	}
+	nodeClaims := &v1.NodeClaimList{}
+	if err := c.kubeClient.List(ctx, nodeClaims, client.MatchingLabels{v1.NodePoolLabelKey: nodePool}); err != nil {
+		log.FromContext(ctx).V(1).Info("Cannot list NodeClaims for zone balancing", "nodePool", nodePool, "error", err)
+		return counts
+	}
+	for _, nodeClaim := range nodeClaims.Items {
+		if zone, ok := nodeClaim.Labels[corev1.LabelTopologyZone]; ok && nodeClaim.DeletionTimestamp.IsZero() {
+			counts[zone]++
+		}
+	}
+	return counts
+}
+
+// quotaFits returns true if one more instance of the instance type fits in the quota
+func quotaFits(quota *ovhclient.InstanceQuota, it *cloudprovider.InstanceType) bool {
+	vcpus := int(it.Capacity.Cpu().Value())
+	ramMiB := int(it.Capacity.Memory().Value() / (1024 * 1024))
+	return quota.Fits(vcpus, ramMiB)
+}
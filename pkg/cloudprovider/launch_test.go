@@ -0,0 +1,238 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+
+	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
+)
+
+// testZonalInstanceType returns an instance type with an on-demand offering per zone at the given prices
+func testZonalInstanceType(name string, vcpus, ramGiB int, prices map[string]float64) *cloudprovider.InstanceType {
+	var zones []string
+	var offerings cloudprovider.Offerings
+	for zone, price := range prices {
+		zones = append(zones, zone)
+		offerings = append(offerings, &cloudprovider.Offering{
+			Requirements: scheduling.NewRequirements(
+				scheduling.NewRequirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, zone),
+				scheduling.NewRequirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpIn, v1.CapacityTypeOnDemand),
+			),
+			Price:     price,
+			Available: true,
+		})
+	}
+	return &cloudprovider.InstanceType{
+		Name: name,
+		Requirements: scheduling.NewRequirements(
+			scheduling.NewRequirement(corev1.LabelInstanceTypeStable, corev1.NodeSelectorOpIn, name),
+			scheduling.NewRequirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, zones...),
+			scheduling.NewRequirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpIn, v1.CapacityTypeOnDemand),
+		),
+		Offerings: offerings,
+		Capacity: corev1.ResourceList{
+			corev1.ResourceCPU:    *resource.NewQuantity(int64(vcpus), resource.DecimalSI),
+			corev1.ResourceMemory: resource.MustParse(fmt.Sprintf("%dGi", ramGiB)),
+		},
+	}
+}
+
+// testZoneNodeClaim returns a launched NodeClaim of the default NodePool in a zone
+func testZoneNodeClaim(name, zone string) *v1.NodeClaim {
+	return &v1.NodeClaim{ObjectMeta: metav1.ObjectMeta{
+		Name:   name,
+		Labels: map[string]string{v1.NodePoolLabelKey: "default", corev1.LabelTopologyZone: zone},
+	}}
+}
+
+func TestLaunchCandidates(t *testing.T) {
+	tests := []struct {
+		name          string
+		instanceTypes []*cloudprovider.InstanceType
+		requirements  []v1.NodeSelectorRequirementWithMinValues
+		// nodeClaims are the NodeClaims the NodePool already launched
+		nodeClaims  []*v1.NodeClaim
+		unavailable [][2]string
+		// want are the flavor/zone pairs of the candidates, in order
+		want []string
+	}{
+		{
+			name: "cheapest first",
+			instanceTypes: []*cloudprovider.InstanceType{
+				testZonalInstanceType("b3-16", 4, 16, map[string]float64{"zone-a": 0.2}),
+				testZonalInstanceType("b3-8", 2, 8, map[string]float64{"zone-a": 0.1}),
+			},
+			want: []string{"b3-8/zone-a", "b3-16/zone-a"},
+		},
+		{
+			name: "same price ordered by name",
+			instanceTypes: []*cloudprovider.InstanceType{
+				testZonalInstanceType("c3-8", 4, 8, map[string]float64{"zone-a": 0.1}),
+				testZonalInstanceType("b3-8", 2, 8, map[string]float64{"zone-a": 0.1}),
+			},
+			want: []string{"b3-8/zone-a", "c3-8/zone-a"},
+		},
+		{
+			name: "zones of a flavor ordered by NodePool nodes",
+			instanceTypes: []*cloudprovider.InstanceType{
+				testZonalInstanceType("b3-8", 2, 8, map[string]float64{"zone-a": 0.1, "zone-b": 0.1, "zone-c": 0.1}),
+			},
+			nodeClaims: []*v1.NodeClaim{
+				testZoneNodeClaim("default-1", "zone-a"),
+				testZoneNodeClaim("default-2", "zone-a"),
+				testZoneNodeClaim("default-3", "zone-c"),
+			},
+			want: []string{"b3-8/zone-b", "b3-8/zone-c", "b3-8/zone-a"},
+		},
+		{
+			name: "price before zone spread",
+			instanceTypes: []*cloudprovider.InstanceType{
+				testZonalInstanceType("b3-8", 2, 8, map[string]float64{"zone-a": 0.1, "zone-b": 0.2}),
+			},
+			nodeClaims: []*v1.NodeClaim{testZoneNodeClaim("default-1", "zone-a")},
+			want:       []string{"b3-8/zone-a", "b3-8/zone-b"},
+		},
+		{
+			name: "zone requirement",
+			instanceTypes: []*cloudprovider.InstanceType{
+				testZonalInstanceType("b3-8", 2, 8, map[string]float64{"zone-a": 0.1, "zone-b": 0.2}),
+			},
+			requirements: []v1.NodeSelectorRequirementWithMinValues{
+				{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"zone-a"}}},
+			},
+			want: []string{"b3-8/zone-b"},
+		},
+		{
+			name: "instance type requirement",
+			instanceTypes: []*cloudprovider.InstanceType{
+				testZonalInstanceType("b3-16", 4, 16, map[string]float64{"zone-a": 0.2}),
+				testZonalInstanceType("b3-8", 2, 8, map[string]float64{"zone-a": 0.1}),
+			},
+			requirements: []v1.NodeSelectorRequirementWithMinValues{
+				{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: corev1.LabelInstanceTypeStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"b3-16"}}},
+			},
+			want: []string{"b3-16/zone-a"},
+		},
+		{
+			name: "recently failed offering skipped",
+			instanceTypes: []*cloudprovider.InstanceType{
+				testZonalInstanceType("b3-8", 2, 8, map[string]float64{"zone-a": 0.1, "zone-b": 0.2}),
+			},
+			unavailable: [][2]string{{"b3-8", "zone-a"}},
+			want:        []string{"b3-8/zone-b"},
+		},
+		{
+			name: "bounded",
+			instanceTypes: []*cloudprovider.InstanceType{
+				testZonalInstanceType("b3-8", 2, 8, map[string]float64{"zone-a": 0.1, "zone-b": 0.1, "zone-c": 0.1}),
+				testZonalInstanceType("b3-16", 4, 16, map[string]float64{"zone-a": 0.2, "zone-b": 0.2, "zone-c": 0.2}),
+			},
+			want: []string{"b3-8/zone-a", "b3-8/zone-b", "b3-8/zone-c", "b3-16/zone-a", "b3-16/zone-b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ovhClient := newFakeMKS(t)
+			c := newTestCloudProvider(ovhClient)
+			builder := fakeclient.NewClientBuilder().WithScheme(scheme.Scheme)
+			for _, nodeClaim := range tt.nodeClaims {
+				builder = builder.WithObjects(nodeClaim)
+			}
+			c.kubeClient = builder.Build()
+			for _, offering := range tt.unavailable {
+				c.unavailableOfferings.MarkUnavailable(offering[0], offering[1], "insufficient_capacity")
+			}
+
+			nodeClaim := &v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{v1.NodePoolLabelKey: "default"}},
+				Spec:       v1.NodeClaimSpec{Requirements: tt.requirements},
+			}
+			got := []string{}
+			for _, candidate := range c.launchCandidates(context.Background(), nodeClaim, tt.instanceTypes) {
+				got = append(got, candidate.InstanceType.Name+"/"+candidate.Zone)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("launchCandidates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLaunchCandidatesQuota(t *testing.T) {
+	tests := []struct {
+		name  string
+		quota *ovhclient.InstanceQuota
+		want  []string
+	}{
+		{
+			name: "quota unreadable",
+			want: []string{"b3-8", "b3-16"},
+		},
+		{
+			name:  "headroom for both",
+			quota: &ovhclient.InstanceQuota{MaxCores: 100, MaxRAM: 102400, MaxInstances: 10},
+			want:  []string{"b3-8", "b3-16"},
+		},
+		{
+			name:  "cores exhausted for the larger flavor",
+			quota: &ovhclient.InstanceQuota{MaxCores: 20, UsedCores: 17},
+			want:  []string{"b3-8"},
+		},
+		{
+			name:  "RAM exhausted for the larger flavor",
+			quota: &ovhclient.InstanceQuota{MaxRAM: 20480, UsedRAM: 8192},
+			want:  []string{"b3-8"},
+		},
+		{
+			name:  "instances exhausted",
+			quota: &ovhclient.InstanceQuota{MaxInstances: 5, UsedInstances: 5},
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, ovhClient := newFakeMKS(t)
+			fake.quota = tt.quota
+			c := newTestCloudProvider(ovhClient)
+			instanceTypes := []*cloudprovider.InstanceType{
+				testZonalInstanceType("b3-16", 4, 16, map[string]float64{"zone-a": 0.2}),
+				testZonalInstanceType("b3-8", 2, 8, map[string]float64{"zone-a": 0.1}),
+			}
+
+			got := []string{}
+			for _, candidate := range c.launchCandidates(context.Background(), &v1.NodeClaim{}, instanceTypes) {
+				got = append(got, candidate.InstanceType.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("launchCandidates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
+)
+
+// abandonedLaunch is a launch that gave up before the node it scaled its pool up for was deleted
+type abandonedLaunch struct {
+	// existingNodeIDs are the nodes listed before the launch scaled the pool up
+	existingNodeIDs map[string]bool
+	// node is the node of the launch, once identified
+	node *ovhclient.Node
+	at   time.Time
+}
+
+// LaunchReaper deletes the nodes of abandoned launches once they show up in their pool
+// A launch that times out before MKS lists its node cannot name the node to remove. Rather than
+// lowering the desired count and letting MKS pick a node, the reaper claims the extra node when it
+// appears, the same way a launch would, and deletes it. It runs on the leader, where launches run.
+type LaunchReaper struct {
+	cloudProvider *CloudProvider
+	interval      time.Duration
+}
+
+// NewLaunchReaper creates a new launch reaper
+func NewLaunchReaper(cloudProvider *CloudProvider, interval time.Duration) *LaunchReaper {
+	return &LaunchReaper{
+		cloudProvider: cloudProvider,
+		interval:      interval,
+	}
+}
+
+// Start reaps the abandoned launches every interval until the context is cancelled
+func (r *LaunchReaper) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.cloudProvider.reapAbandonedLaunches(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection ensures the reaper runs where the launches are recorded
+func (r *LaunchReaper) NeedLeaderElection() bool {
+	return true
+}
+
+// recordAbandonedLaunch records a launch whose node is left to the reaper
+func (c *CloudProvider) recordAbandonedLaunch(poolID string, launch abandonedLaunch) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.abandonedLaunches[poolID] = append(c.abandonedLaunches[poolID], launch)
+}
+
+// reapAbandonedLaunches deletes the nodes of abandoned launches that showed up
+// Launches whose pool is gone are dropped, and so are launches whose node did not show up within
+// AbandonedLaunchTTL: the node is then left to garbage collection.
+func (c *CloudProvider) reapAbandonedLaunches(ctx context.Context) {
+	c.mu.Lock()
+	abandoned := c.abandonedLaunches
+	c.abandonedLaunches = make(map[string][]abandonedLaunch)
+	c.mu.Unlock()
+
+	for poolID, launches := range abandoned {
+		nodes, err := c.ovhClient.ListPoolNodes(ctx, poolID)
+		if err != nil {
+			if !ovhclient.IsNotFound(err) {
+				log.FromContext(ctx).V(1).Info("Failed to list the nodes of abandoned launches", "poolID", poolID, "error", err)
+				for _, launch := range launches {
+					c.recordAbandonedLaunch(poolID, launch)
+				}
+			}
+			continue
+		}
+
+		for _, launch := range launches {
+			if launch.node == nil {
+				node, found := c.claimOrphanNode(poolID, nodes, launch.existingNodeIDs)
+				if !found {
+					if time.Since(launch.at) < AbandonedLaunchTTL {
+						c.recordAbandonedLaunch(poolID, launch)
+					}
+					continue
+				}
+				launch.node = &node
+			}
+			if !c.deleteFailedNode(ctx, poolID, launch.node) {
+				c.recordAbandonedLaunch(poolID, launch)
+			}
+		}
+	}
+}
+
+// claimOrphanNode claims a new node of a pool that no waiting launch is left to claim
+// New nodes are those not listed before the abandoned launch and not claimed yet. As many of them
+// as there are launches still waiting on the pool are left to those launches.
+func (c *CloudProvider) claimOrphanNode(poolID string, nodes []ovhclient.Node, existingNodeIDs map[string]bool) (ovhclient.Node, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var unclaimed []ovhclient.Node
+	for _, node := range nodes {
+		if !existingNodeIDs[node.ID] && !c.claimedNodes[poolID][node.ID] {
+			unclaimed = append(unclaimed, node)
+		}
+	}
+	if len(unclaimed) <= c.waitingLaunches[poolID] {
+		return ovhclient.Node{}, false
+	}
+
+	// Waiting launches claim the first unclaimed node, the reaper takes the last one
+	node := unclaimed[len(unclaimed)-1]
+	if c.claimedNodes[poolID] == nil {
+		c.claimedNodes[poolID] = make(map[string]bool)
+	}
+	c.claimedNodes[poolID][node.ID] = true
+	return node, true
+}
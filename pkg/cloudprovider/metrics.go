@@ -17,8 +17,12 @@ limitations under the License.
 package ovhcloud
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
 )
 
 const (
@@ -113,6 +117,33 @@ var (
 		[]string{"method", "endpoint"},
 	)
 
+	apiQueueWaitDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: metricsSubsystem,
+			Name:      "api_queue_wait_seconds",
+			Help:      "Time OVH API calls waited on the client-side rate limiter",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12), // 10ms, 20ms, ... 20.48s
+		},
+		[]string{"class"},
+	)
+
+	apiCircuitBreakerState = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: metricsSubsystem,
+			Name:      "api_circuit_breaker_state",
+			Help:      "State of the OVH API circuit breaker (0 closed, 1 half-open, 2 open)",
+		},
+	)
+
+	offeringUnavailableTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: metricsSubsystem,
+			Name:      "offering_unavailable_total",
+			Help:      "Total number of times a flavor and zone pair was marked temporarily unavailable",
+		},
+		[]string{"flavor", "zone", "reason"},
+	)
+
 	// Instance type metrics
 	instanceTypesAvailable = prometheus.NewGauge(
 		prometheus.GaugeOpts{
@@ -147,6 +178,33 @@ var (
 		},
 	)
 
+	pricingRefreshFailures = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Subsystem: metricsSubsystem,
+			Name:      "pricing_refresh_failures_total",
+			Help:      "Total number of failed pricing catalog refreshes",
+		},
+	)
+
+	// Cost metrics
+	estimatedHourlyCost = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: metricsSubsystem,
+			Name:      "estimated_hourly_cost",
+			Help:      "Estimated hourly cost in EUR of running Karpenter-managed nodes",
+		},
+		[]string{"nodepool", "flavor", "zone", "billing"},
+	)
+
+	nodeHoursTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: metricsSubsystem,
+			Name:      "node_hours_total",
+			Help:      "Accumulated hours of running Karpenter-managed nodes",
+		},
+		[]string{"nodepool", "flavor", "zone", "billing"},
+	)
+
 	// Drift metrics
 	driftDetectionTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -156,6 +214,16 @@ var (
 		},
 		[]string{"reason"},
 	)
+
+	// Interruption metrics
+	interruptionEventsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: metricsSubsystem,
+			Name:      "interruption_events_total",
+			Help:      "Total number of maintenance and migration events handled for Karpenter-managed nodes",
+		},
+		[]string{"kind", "action"},
+	)
 )
 
 func init() {
@@ -170,11 +238,18 @@ func init() {
 		apiCallsTotal,
 		apiCallDuration,
 		apiRetriesTotal,
+		apiQueueWaitDuration,
+		apiCircuitBreakerState,
+		offeringUnavailableTotal,
 		instanceTypesAvailable,
 		pricingCacheHits,
 		pricingCacheMisses,
 		pricingCacheRefreshes,
+		pricingRefreshFailures,
+		estimatedHourlyCost,
+		nodeHoursTotal,
 		driftDetectionTotal,
+		interruptionEventsTotal,
 	)
 }
 
@@ -223,6 +298,53 @@ func RecordAPIRetry(method, endpoint string) {
 	apiRetriesTotal.WithLabelValues(method, endpoint).Inc()
 }
 
+// RecordAPIQueueWait records the time an API call waited on the client-side rate limiter
+func RecordAPIQueueWait(class string, waitSeconds float64) {
+	apiQueueWaitDuration.WithLabelValues(class).Observe(waitSeconds)
+}
+
+// RecordOfferingUnavailable records a flavor and zone pair marked temporarily unavailable
+func RecordOfferingUnavailable(flavor, zone, reason string) {
+	offeringUnavailableTotal.WithLabelValues(flavor, zone, reason).Inc()
+}
+
+// SetAPICircuitBreakerState sets the state of the OVH API circuit breaker
+func SetAPICircuitBreakerState(state string) {
+	switch state {
+	case ovhclient.CircuitOpen:
+		apiCircuitBreakerState.Set(2)
+	case ovhclient.CircuitHalfOpen:
+		apiCircuitBreakerState.Set(1)
+	default:
+		apiCircuitBreakerState.Set(0)
+	}
+}
+
+// APIMetricsObserver records OVH API calls made by the OVH client into the API metrics
+// It implements client.APIObserver
+type APIMetricsObserver struct{}
+
+// ObserveAPICall records a single OVH API call attempt and its duration
+func (APIMetricsObserver) ObserveAPICall(method, endpoint, status string, duration time.Duration) {
+	RecordAPICall(method, endpoint, status)
+	RecordAPICallDuration(method, endpoint, duration.Seconds())
+}
+
+// ObserveAPIRetry records a retried OVH API call
+func (APIMetricsObserver) ObserveAPIRetry(method, endpoint string) {
+	RecordAPIRetry(method, endpoint)
+}
+
+// ObserveAPIQueueWait records the rate limiter wait of an OVH API call
+func (APIMetricsObserver) ObserveAPIQueueWait(class string, wait time.Duration) {
+	RecordAPIQueueWait(class, wait.Seconds())
+}
+
+// ObserveCircuitState records a state change of the OVH API circuit breaker
+func (APIMetricsObserver) ObserveCircuitState(state string) {
+	SetAPICircuitBreakerState(state)
+}
+
 // SetInstanceTypesAvailable sets the number of available instance types
 func SetInstanceTypesAvailable(count int) {
 	instanceTypesAvailable.Set(float64(count))
@@ -243,7 +365,87 @@ func RecordPricingCacheRefresh() {
 	pricingCacheRefreshes.Inc()
 }
 
+// RecordPricingRefreshFailure records a failed pricing catalog refresh
+func RecordPricingRefreshFailure() {
+	pricingRefreshFailures.Inc()
+}
+
+// PricingMetricsObserver records pricing client events into the pricing cache metrics
+// It implements client.PricingObserver
+type PricingMetricsObserver struct{}
+
+// ObservePricingCacheHit records a price served from the catalog
+func (PricingMetricsObserver) ObservePricingCacheHit() {
+	RecordPricingCacheHit()
+}
+
+// ObservePricingCacheMiss records a price served by estimation
+func (PricingMetricsObserver) ObservePricingCacheMiss() {
+	RecordPricingCacheMiss()
+}
+
+// ObservePricingCacheRefresh records a successful catalog refresh
+func (PricingMetricsObserver) ObservePricingCacheRefresh() {
+	RecordPricingCacheRefresh()
+}
+
+// ObservePricingRefreshFailure records a failed catalog refresh
+func (PricingMetricsObserver) ObservePricingRefreshFailure() {
+	RecordPricingRefreshFailure()
+}
+
+// RegisterPricingMetrics registers the catalog staleness gauges of a pricing client
+// Must be called at most once, with the pricing client used by the cloud provider
+func RegisterPricingMetrics(pricingClient *ovhclient.PricingClient) {
+	metrics.Registry.MustRegister(
+		prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Subsystem: metricsSubsystem,
+				Name:      "pricing_catalog_age_seconds",
+				Help:      "Time since the pricing catalog was last refreshed",
+			},
+			func() float64 { return pricingClient.CatalogAge().Seconds() },
+		),
+		prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Subsystem: metricsSubsystem,
+				Name:      "pricing_priced_flavors",
+				Help:      "Number of flavors priced by the pricing catalog",
+			},
+			func() float64 { return float64(pricingClient.PricedFlavorCount()) },
+		),
+		prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Subsystem: metricsSubsystem,
+				Name:      "pricing_estimated_flavors",
+				Help:      "Number of flavors served by price estimation since the last catalog refresh",
+			},
+			func() float64 { return float64(pricingClient.EstimatedFlavorCount()) },
+		),
+	)
+}
+
+// DeleteEstimatedHourlyCost removes the cost gauge of a group that has no running nodes anymore
+func DeleteEstimatedHourlyCost(nodePool, flavor, zone, billing string) {
+	estimatedHourlyCost.DeleteLabelValues(nodePool, flavor, zone, billing)
+}
+
+// SetEstimatedHourlyCost sets the hourly cost of the nodes of a NodePool, flavor, zone and billing mode
+func SetEstimatedHourlyCost(nodePool, flavor, zone, billing string, cost float64) {
+	estimatedHourlyCost.WithLabelValues(nodePool, flavor, zone, billing).Set(cost)
+}
+
+// RecordNodeHours accumulates running node hours
+func RecordNodeHours(nodePool, flavor, zone, billing string, hours float64) {
+	nodeHoursTotal.WithLabelValues(nodePool, flavor, zone, billing).Add(hours)
+}
+
 // RecordDriftDetection records a drift detection
 func RecordDriftDetection(reason string) {
 	driftDetectionTotal.WithLabelValues(reason).Inc()
 }
+
+// RecordInterruptionEvent records the handling of a maintenance or migration event
+func RecordInterruptionEvent(kind, action string) {
+	interruptionEventsTotal.WithLabelValues(kind, action).Inc()
+}
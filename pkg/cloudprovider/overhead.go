@@ -0,0 +1,227 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis/v1alpha1"
+)
+
+// reservationTier is one step of a tiered reservation: fraction of the resource between From and To
+type reservationTier struct {
+	From     int64
+	To       int64 // 0 means unbounded
+	Fraction float64
+}
+
+// kubeReservedMemoryTiers estimates the memory reserved by MKS kubelets (in MiB)
+// MKS does not publish its kubelet reservations, so this is the tiered formula of GKE. It is only
+// used until a node registers: the reservations of MKS nodes are then learned from their allocatable
+// (see AllocatableCache and learnedAllocatable):
+// 25% of the first 4GiB, 20% of the next 4GiB, 10% of the next 8GiB,
+// 6% of the next 112GiB and 2% of anything above 128GiB
+var kubeReservedMemoryTiers = []reservationTier{
+	{From: 0, To: 4 * 1024, Fraction: 0.25},
+	{From: 4 * 1024, To: 8 * 1024, Fraction: 0.20},
+	{From: 8 * 1024, To: 16 * 1024, Fraction: 0.10},
+	{From: 16 * 1024, To: 128 * 1024, Fraction: 0.06},
+	{From: 128 * 1024, To: 0, Fraction: 0.02},
+}
+
+// kubeReservedCPUTiers estimates the CPU reserved by MKS kubelets (in millicores), with the GKE formula
+// 6% of the first core, 1% of the second, 0.5% of the next two and 0.25% of anything above four
+var kubeReservedCPUTiers = []reservationTier{
+	{From: 0, To: 1000, Fraction: 0.06},
+	{From: 1000, To: 2000, Fraction: 0.01},
+	{From: 2000, To: 4000, Fraction: 0.005},
+	{From: 4000, To: 0, Fraction: 0.0025},
+}
+
+const (
+	// systemReservedCPU and systemReservedMemory cover the OS daemons (systemd, containerd, sshd)
+	systemReservedCPU    = "100m"
+	systemReservedMemory = "100Mi"
+
+	// evictionHardMemory matches the kubelet memory.available hard eviction threshold
+	evictionHardMemory = "100Mi"
+	// evictionHardStoragePercent matches the kubelet nodefs.available hard eviction threshold
+	evictionHardStoragePercent = 10
+)
+
+// tieredReservation sums the reserved amount of a resource across all tiers
+func tieredReservation(total int64, tiers []reservationTier) int64 {
+	var reserved float64
+	for _, tier := range tiers {
+		if total <= tier.From {
+			break
+		}
+		upper := total
+		if tier.To != 0 && tier.To < upper {
+			upper = tier.To
+		}
+		reserved += float64(upper-tier.From) * tier.Fraction
+	}
+	return int64(reserved)
+}
+
+// computeOverhead estimates the kube-reserved, system-reserved and eviction thresholds of an MKS node
+// from its capacity. Resources set in the NodeClass overhead configuration replace the computed values.
+func computeOverhead(capacity corev1.ResourceList, overrides *v1alpha1.OverheadConfiguration) *cloudprovider.InstanceTypeOverhead {
+	cpuMilli := capacity.Cpu().MilliValue()
+	memoryMiB := capacity.Memory().Value() / (1024 * 1024)
+	storage := capacity.StorageEphemeral().Value()
+
+	overhead := &cloudprovider.InstanceTypeOverhead{
+		KubeReserved: corev1.ResourceList{
+			corev1.ResourceCPU:    *resource.NewMilliQuantity(tieredReservation(cpuMilli, kubeReservedCPUTiers), resource.DecimalSI),
+			corev1.ResourceMemory: *resource.NewQuantity(tieredReservation(memoryMiB, kubeReservedMemoryTiers)*1024*1024, resource.BinarySI),
+		},
+		SystemReserved: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(systemReservedCPU),
+			corev1.ResourceMemory: resource.MustParse(systemReservedMemory),
+		},
+		EvictionThreshold: corev1.ResourceList{
+			corev1.ResourceMemory:           resource.MustParse(evictionHardMemory),
+			corev1.ResourceEphemeralStorage: *resource.NewQuantity(storage*evictionHardStoragePercent/100, resource.BinarySI),
+		},
+	}
+
+	if overrides != nil {
+		mergeResources(overhead.KubeReserved, overrides.KubeReserved)
+		mergeResources(overhead.SystemReserved, overrides.SystemReserved)
+		mergeResources(overhead.EvictionThreshold, overrides.EvictionThreshold)
+	}
+
+	return overhead
+}
+
+// mergeResources copies every resource of src into dst, replacing existing values
+func mergeResources(dst, src corev1.ResourceList) {
+	for name, quantity := range src {
+		dst[name] = quantity.DeepCopy()
+	}
+}
+
+// reservationPoint is the amount of a resource MKS reserved on the nodes of a flavor of a given capacity
+type reservationPoint struct {
+	Capacity int64
+	Reserved int64
+}
+
+// reservationPoints returns, per observed resource, what MKS reserved on the registered nodes of each
+// flavor, sorted by capacity. Amounts are in millicores for CPU and bytes otherwise.
+func reservationPoints(instanceTypes []*cloudprovider.InstanceType, cache *AllocatableCache) map[corev1.ResourceName][]reservationPoint {
+	points := make(map[corev1.ResourceName][]reservationPoint)
+	if cache.Len() == 0 {
+		return points
+	}
+	for _, it := range instanceTypes {
+		observed, ok := cache.Get(it.Name)
+		if !ok {
+			continue
+		}
+		for _, name := range observedResources {
+			capacity, hasCapacity := it.Capacity[name]
+			allocatable, hasAllocatable := observed[name]
+			if !hasCapacity || !hasAllocatable || capacity.IsZero() {
+				continue
+			}
+			reserved := capacity.DeepCopy()
+			reserved.Sub(allocatable)
+			points[name] = append(points[name], reservationPoint{
+				Capacity: quantityValue(name, capacity),
+				Reserved: max(quantityValue(name, reserved), 0),
+			})
+		}
+	}
+	for name := range points {
+		sort.Slice(points[name], func(i, j int) bool { return points[name][i].Capacity < points[name][j].Capacity })
+	}
+	return points
+}
+
+// learnedAllocatable estimates the allocatable resources of a flavor no node registered for yet from
+// what MKS reserved on the registered nodes of other flavors
+// Resources overridden in the NodeClass keep the configured overhead.
+func learnedAllocatable(capacity corev1.ResourceList, points map[corev1.ResourceName][]reservationPoint, overrides *v1alpha1.OverheadConfiguration) corev1.ResourceList {
+	allocatable := corev1.ResourceList{}
+	for name, observed := range points {
+		total, ok := capacity[name]
+		if !ok || len(observed) == 0 || isOverridden(name, overrides) {
+			continue
+		}
+		reserved := interpolateReservation(quantityValue(name, total), observed)
+		if name == corev1.ResourceCPU {
+			allocatable[name] = *resource.NewMilliQuantity(max(total.MilliValue()-reserved, 0), resource.DecimalSI)
+		} else {
+			allocatable[name] = *resource.NewQuantity(max(total.Value()-reserved, 0), resource.BinarySI)
+		}
+	}
+	return allocatable
+}
+
+// interpolateReservation estimates the amount reserved for a capacity from sorted observations
+// Between two observed capacities the reservation is interpolated linearly. Above the largest it
+// follows the slope of the two largest, or grows proportionally with a single observation. Below the
+// smallest the smallest reservation is kept, so that small flavors are not over-packed.
+func interpolateReservation(capacity int64, points []reservationPoint) int64 {
+	first, last := points[0], points[len(points)-1]
+	switch {
+	case capacity <= first.Capacity:
+		return first.Reserved
+	case capacity >= last.Capacity && len(points) == 1:
+		return int64(float64(capacity) * float64(last.Reserved) / float64(last.Capacity))
+	case capacity >= last.Capacity:
+		previous := points[len(points)-2]
+		slope := 0.0
+		if last.Capacity > previous.Capacity {
+			slope = max(float64(last.Reserved-previous.Reserved)/float64(last.Capacity-previous.Capacity), 0)
+		}
+		return last.Reserved + int64(float64(capacity-last.Capacity)*slope)
+	}
+	upper := sort.Search(len(points), func(i int) bool { return points[i].Capacity >= capacity })
+	lower := points[upper-1]
+	if points[upper].Capacity == lower.Capacity {
+		return points[upper].Reserved
+	}
+	fraction := float64(capacity-lower.Capacity) / float64(points[upper].Capacity-lower.Capacity)
+	return lower.Reserved + int64(fraction*float64(points[upper].Reserved-lower.Reserved))
+}
+
+// quantityValue returns a quantity in millicores for CPU and in base units otherwise
+func quantityValue(name corev1.ResourceName, quantity resource.Quantity) int64 {
+	if name == corev1.ResourceCPU {
+		return quantity.MilliValue()
+	}
+	return quantity.Value()
+}
+
+// isOverridden returns true if the NodeClass overhead configuration sets a resource
+func isOverridden(name corev1.ResourceName, overrides *v1alpha1.OverheadConfiguration) bool {
+	if overrides == nil {
+		return false
+	}
+	_, kube := overrides.KubeReserved[name]
+	_, system := overrides.SystemReserved[name]
+	_, eviction := overrides.EvictionThreshold[name]
+	return kube || system || eviction
+}
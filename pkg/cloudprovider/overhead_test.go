@@ -0,0 +1,177 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis/v1alpha1"
+)
+
+func testCapacity(cpu, memory, storage string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:              resource.MustParse(cpu),
+		corev1.ResourceMemory:           resource.MustParse(memory),
+		corev1.ResourceEphemeralStorage: resource.MustParse(storage),
+	}
+}
+
+func TestComputeOverhead(t *testing.T) {
+	tests := []struct {
+		name      string
+		capacity  corev1.ResourceList
+		overrides *v1alpha1.OverheadConfiguration
+		want      cloudprovider.InstanceTypeOverhead
+	}{
+		{
+			name:     "small flavor",
+			capacity: testCapacity("2", "7Gi", "50Gi"),
+			want: cloudprovider.InstanceTypeOverhead{
+				// 6% of the first core and 1% of the second; 25% of 4GiB and 20% of the next 3GiB
+				KubeReserved:      corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("70m"), corev1.ResourceMemory: resource.MustParse("1638Mi")},
+				SystemReserved:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("100Mi")},
+				EvictionThreshold: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("100Mi"), corev1.ResourceEphemeralStorage: resource.MustParse("5Gi")},
+			},
+		},
+		{
+			name:     "large flavor",
+			capacity: testCapacity("32", "256Gi", "400Gi"),
+			want: cloudprovider.InstanceTypeOverhead{
+				// 60m + 10m + 10m + 28 cores at 0.25%; 1GiB + 819.2MiB + 819.2MiB + 6881.28MiB + 2621.44MiB
+				KubeReserved:      corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("150m"), corev1.ResourceMemory: resource.MustParse("12165Mi")},
+				SystemReserved:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("100Mi")},
+				EvictionThreshold: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("100Mi"), corev1.ResourceEphemeralStorage: resource.MustParse("40Gi")},
+			},
+		},
+		{
+			name:     "overridden resources",
+			capacity: testCapacity("2", "7Gi", "50Gi"),
+			overrides: &v1alpha1.OverheadConfiguration{
+				KubeReserved:      corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				EvictionThreshold: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("500Mi")},
+			},
+			want: cloudprovider.InstanceTypeOverhead{
+				KubeReserved:      corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("70m"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+				SystemReserved:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("100Mi")},
+				EvictionThreshold: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("500Mi"), corev1.ResourceEphemeralStorage: resource.MustParse("5Gi")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeOverhead(tt.capacity, tt.overrides)
+			for bucket, lists := range map[string][2]corev1.ResourceList{
+				"kubeReserved":      {got.KubeReserved, tt.want.KubeReserved},
+				"systemReserved":    {got.SystemReserved, tt.want.SystemReserved},
+				"evictionThreshold": {got.EvictionThreshold, tt.want.EvictionThreshold},
+			} {
+				got, want := lists[0], lists[1]
+				if len(got) != len(want) {
+					t.Errorf("%s = %v, want %v", bucket, got, want)
+				}
+				for name, quantity := range want {
+					if actual := got[name]; actual.Cmp(quantity) != 0 {
+						t.Errorf("%s %s = %s, want %s", bucket, name, actual.String(), quantity.String())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestInterpolateReservation(t *testing.T) {
+	points := []reservationPoint{{Capacity: 8, Reserved: 2}, {Capacity: 16, Reserved: 3}, {Capacity: 32, Reserved: 4}}
+	tests := []struct {
+		name     string
+		capacity int64
+		points   []reservationPoint
+		want     int64
+	}{
+		{name: "observed capacity", capacity: 16, points: points, want: 3},
+		{name: "between observations", capacity: 24, points: points, want: 3},
+		{name: "below the smallest", capacity: 4, points: points, want: 2},
+		{name: "above the largest", capacity: 64, points: points, want: 6},
+		{name: "single observation scales proportionally", capacity: 32, points: points[:1], want: 8},
+		{name: "single observation below", capacity: 4, points: points[:1], want: 2},
+		{name: "decreasing reservations do not extrapolate below the largest", capacity: 64,
+			points: []reservationPoint{{Capacity: 8, Reserved: 4}, {Capacity: 16, Reserved: 3}}, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := interpolateReservation(tt.capacity, tt.points); got != tt.want {
+				t.Errorf("interpolateReservation(%d) = %d, want %d", tt.capacity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLearnedAllocatable(t *testing.T) {
+	small := &cloudprovider.InstanceType{Name: "b3-8", Capacity: testCapacity("2", "8Gi", "50Gi")}
+	large := &cloudprovider.InstanceType{Name: "b3-32", Capacity: testCapacity("8", "32Gi", "200Gi")}
+	cache := NewAllocatableCache(nil, "karpenter")
+	cache.observed["b3-8"] = testCapacity("1900m", "6Gi", "45Gi")
+	cache.observed["b3-32"] = testCapacity("7700m", "28Gi", "180Gi")
+	points := reservationPoints([]*cloudprovider.InstanceType{small, large}, cache)
+
+	tests := []struct {
+		name      string
+		capacity  corev1.ResourceList
+		overrides *v1alpha1.OverheadConfiguration
+		want      corev1.ResourceList
+	}{
+		{
+			name:     "between the observed flavors",
+			capacity: testCapacity("4", "16Gi", "100Gi"),
+			// 100m and 300m, 2Gi and 4Gi, 5Gi and 20Gi reserved on the observed flavors
+			want: corev1.ResourceList{
+				corev1.ResourceCPU:              resource.MustParse("3834m"),
+				corev1.ResourceMemory:           resource.MustParse("13653Mi"),
+				corev1.ResourceEphemeralStorage: resource.MustParse("90Gi"),
+			},
+		},
+		{
+			name:      "overridden resources keep the configured overhead",
+			capacity:  testCapacity("4", "16Gi", "100Gi"),
+			overrides: &v1alpha1.OverheadConfiguration{SystemReserved: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}},
+			want: corev1.ResourceList{
+				corev1.ResourceCPU:              resource.MustParse("3834m"),
+				corev1.ResourceEphemeralStorage: resource.MustParse("90Gi"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := learnedAllocatable(tt.capacity, points, tt.overrides)
+			if len(got) != len(tt.want) {
+				t.Errorf("learnedAllocatable = %v, want %v", got, tt.want)
+			}
+			for name, want := range tt.want {
+				actual := got[name]
+				if diff := actual.Value() - want.Value(); name != corev1.ResourceCPU && (diff > 1<<20 || diff < -(1<<20)) {
+					t.Errorf("%s = %s, want %s", name, actual.String(), want.String())
+				}
+				if name == corev1.ResourceCPU && actual.MilliValue() != want.MilliValue() {
+					t.Errorf("%s = %s, want %s", name, actual.String(), want.String())
+				}
+			}
+		})
+	}
+}
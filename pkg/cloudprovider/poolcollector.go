@@ -0,0 +1,160 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
+)
+
+var (
+	poolLabels = []string{"pool", "flavor", "zone", "status", "monthly_billed"}
+
+	poolDesiredNodesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("", metricsSubsystem, "pool_desired_nodes"),
+		"Desired number of nodes of a Karpenter-managed pool",
+		poolLabels, nil,
+	)
+
+	poolCurrentNodesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("", metricsSubsystem, "pool_current_nodes"),
+		"Current number of nodes of a Karpenter-managed pool",
+		poolLabels, nil,
+	)
+
+	poolsStuckDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("", metricsSubsystem, "pools_stuck"),
+		"Number of Karpenter-managed pools in a transitional status for longer than the stuck threshold",
+		nil, nil,
+	)
+)
+
+// poolStatusSince tracks when a pool entered its current status
+// MKS updates a pool when its status changes, so the pool update time is used when reported.
+// Otherwise the status is timed from when the collector first saw it.
+type poolStatusSince struct {
+	Status string
+	Since  time.Time
+}
+
+// PoolCollector periodically snapshots the Karpenter-managed node pools from the inventory and exports them as metrics
+// It implements prometheus.Collector and is started as a manager runnable
+type PoolCollector struct {
+	inventory      *Inventory
+	interval       time.Duration
+	stuckThreshold time.Duration
+
+	mu       sync.RWMutex
+	pools    []ovhclient.NodePool
+	statuses map[string]poolStatusSince // pool ID -> status tracking
+}
+
+// NewPoolCollector creates a new pool collector
+func NewPoolCollector(inventory *Inventory, interval, stuckThreshold time.Duration) *PoolCollector {
+	return &PoolCollector{
+		inventory:      inventory,
+		interval:       interval,
+		stuckThreshold: stuckThreshold,
+		statuses:       make(map[string]poolStatusSince),
+	}
+}
+
+// Start snapshots the pools every interval until the context is cancelled
+func (p *PoolCollector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.refresh(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.refresh(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection ensures only the leader refreshes the inventory through the collector
+func (p *PoolCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// refresh lists the pools and updates the snapshot, keeping the previous one on error
+func (p *PoolCollector) refresh(ctx context.Context) {
+	karpenterPools, err := p.inventory.Pools(ctx)
+	if err != nil {
+		log.FromContext(ctx).V(1).Info("Failed to snapshot node pools for metrics", "error", err)
+		return
+	}
+
+	now := time.Now()
+	statuses := make(map[string]poolStatusSince)
+	for _, pool := range karpenterPools {
+		if updatedAt, err := time.Parse(time.RFC3339, pool.UpdatedAt); err == nil {
+			statuses[pool.ID] = poolStatusSince{Status: pool.Status, Since: updatedAt}
+			continue
+		}
+		p.mu.RLock()
+		previous, ok := p.statuses[pool.ID]
+		p.mu.RUnlock()
+		if ok && previous.Status == pool.Status {
+			statuses[pool.ID] = previous
+		} else {
+			statuses[pool.ID] = poolStatusSince{Status: pool.Status, Since: now}
+		}
+	}
+
+	p.mu.Lock()
+	p.pools = karpenterPools
+	p.statuses = statuses
+	p.mu.Unlock()
+
+	SetPoolsActive(len(karpenterPools))
+}
+
+// Describe implements prometheus.Collector
+func (p *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolDesiredNodesDesc
+	ch <- poolCurrentNodesDesc
+	ch <- poolsStuckDesc
+}
+
+// Collect implements prometheus.Collector
+func (p *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	stuck := 0
+	for _, pool := range p.pools {
+		labels := []string{pool.Name, pool.FlavorName, pool.Zone(), pool.Status, strconv.FormatBool(pool.MonthlyBilled)}
+		ch <- prometheus.MustNewConstMetric(poolDesiredNodesDesc, prometheus.GaugeValue, float64(pool.DesiredNodes), labels...)
+		ch <- prometheus.MustNewConstMetric(poolCurrentNodesDesc, prometheus.GaugeValue, float64(pool.CurrentNodes), labels...)
+
+		if status, ok := p.statuses[pool.ID]; ok && isTransitionalPoolStatus(status.Status) && time.Since(status.Since) > p.stuckThreshold {
+			stuck++
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(poolsStuckDesc, prometheus.GaugeValue, float64(stuck))
+}
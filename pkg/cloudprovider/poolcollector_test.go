@@ -0,0 +1,77 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
+)
+
+func TestPoolCollectorStuckPools(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		updatedAt string
+		want      float64
+	}{
+		{name: "transitional since before the threshold", status: PoolStatusResizing, updatedAt: time.Now().Add(-time.Hour).Format(time.RFC3339), want: 1},
+		{name: "transitional since within the threshold", status: PoolStatusResizing, updatedAt: time.Now().Add(-time.Minute).Format(time.RFC3339), want: 0},
+		{name: "ready since before the threshold", status: PoolStatusReady, updatedAt: time.Now().Add(-time.Hour).Format(time.RFC3339), want: 0},
+		{name: "transitional without update time", status: PoolStatusResizing, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, ovhClient := newFakeMKS(t)
+			fake.pools["pool"] = &ovhclient.NodePool{ID: "pool", Name: testPoolName, Status: tt.status, UpdatedAt: tt.updatedAt}
+
+			collector := NewPoolCollector(NewInventory(ovhClient, DefaultInventoryRefreshInterval), time.Minute, 30*time.Minute)
+			collector.refresh(context.Background())
+
+			if got := collectStuckPools(t, collector); got != tt.want {
+				t.Errorf("stuck pools = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// collectStuckPools returns the stuck pools gauge exported by a collector
+func collectStuckPools(t *testing.T, collector *PoolCollector) float64 {
+	t.Helper()
+	ch := make(chan prometheus.Metric, 16)
+	collector.Collect(ch)
+	close(ch)
+
+	for metric := range ch {
+		if metric.Desc() != poolsStuckDesc {
+			continue
+		}
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("writing metric: %v", err)
+		}
+		return m.GetGauge().GetValue()
+	}
+	t.Fatal("stuck pools gauge not collected")
+	return 0
+}
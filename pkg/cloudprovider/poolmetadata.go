@@ -0,0 +1,231 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis/v1alpha1"
+	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+)
+
+const (
+	// poolHashLength is the number of hex characters of the spec hash kept in pool names and labels
+	poolHashLength = 8
+
+	// maxPoolNameLength keeps pool names valid DNS labels (63 characters) once MKS appends
+	// the node suffix to build node names
+	maxPoolNameLength = 57
+)
+
+// poolMetadata describes what a Karpenter-managed pool was created for
+// It is written to the pool template labels on creation and read back from the pool object,
+// so pool names never need to be parsed
+type poolMetadata struct {
+	Flavor        string
+	Zone          string
+	NodeClass     string
+	NodePool      string
+	MonthlyBilled bool
+	// Hash identifies the billing, placement, labels and taints written into the pool template
+	Hash string
+	// NodeClassHash identifies the NodeClass spec the pool was created for
+	NodeClassHash string
+}
+
+// newPoolMetadata describes the pool a NodeClaim is launched in for a flavor and zone
+// The hash is computed from the template the pool is created with, before the metadata labels are added
+func newPoolMetadata(flavor, zone string, nodeClass *v1alpha1.OVHNodeClass, nodeClaim *v1.NodeClaim, template *ovhclient.NodePoolTemplate) poolMetadata {
+	metadata := poolMetadata{
+		Flavor:        flavor,
+		Zone:          zone,
+		NodeClass:     nodeClass.Name,
+		MonthlyBilled: nodeClass.Spec.MonthlyBilled,
+		NodeClassHash: nodeClassHash(nodeClass),
+	}
+	if nodeClaim != nil {
+		metadata.NodePool = nodeClaim.Labels[v1.NodePoolLabelKey]
+	}
+	metadata.Hash = poolTemplateHash(metadata.NodeClass, metadata.NodePool, nodeClass.Spec.MonthlyBilled, nodeClass.Spec.AntiAffinity, template)
+	return metadata
+}
+
+// PoolName returns the pool name: karpenter-{flavor}-{zone}-{hash}
+// The flavor and zone part is truncated when needed, the hash label keeps the name reversible
+func (m poolMetadata) PoolName() string {
+	base := strings.ToLower(strings.ReplaceAll(m.Flavor, ".", "-"))
+	if m.Zone != "" {
+		base = base + "-" + m.Zone
+	}
+	suffix := ""
+	if m.Hash != "" {
+		suffix = "-" + m.Hash
+	}
+	if room := maxPoolNameLength - len(PoolNamePrefix) - len(suffix); len(base) > room {
+		base = strings.TrimRight(base[:room], "-")
+	}
+	return PoolNamePrefix + base + suffix
+}
+
+// Labels returns the pool template labels encoding the metadata
+func (m poolMetadata) Labels() map[string]string {
+	labels := map[string]string{
+		corev1.LabelInstanceTypeStable:  m.Flavor,
+		v1alpha1.LabelPoolMonthlyBilled: strconv.FormatBool(m.MonthlyBilled),
+	}
+	if m.Zone != "" {
+		labels[corev1.LabelTopologyZone] = m.Zone
+	}
+	if m.NodeClass != "" {
+		labels[v1alpha1.LabelPoolNodeClass] = m.NodeClass
+	}
+	if m.NodePool != "" {
+		labels[v1.NodePoolLabelKey] = m.NodePool
+	}
+	if m.Hash != "" {
+		labels[v1alpha1.LabelPoolHash] = m.Hash
+	}
+	if m.NodeClassHash != "" {
+		labels[v1alpha1.LabelPoolNodeClassHash] = m.NodeClassHash
+	}
+	return labels
+}
+
+// parsePoolMetadata reads the metadata of a pool
+// Fields reported by the MKS API (flavor, availability zones, billing) take precedence over
+// the template labels, which fill in what the API does not report and what Karpenter recorded
+func parsePoolMetadata(pool *ovhclient.NodePool) poolMetadata {
+	var labels map[string]string
+	if pool.Template != nil {
+		labels = pool.Template.Metadata.Labels
+	}
+
+	metadata := poolMetadata{
+		Flavor:        pool.FlavorName,
+		Zone:          pool.Zone(),
+		NodeClass:     labels[v1alpha1.LabelPoolNodeClass],
+		NodePool:      labels[v1.NodePoolLabelKey],
+		MonthlyBilled: pool.MonthlyBilled,
+		Hash:          labels[v1alpha1.LabelPoolHash],
+		NodeClassHash: labels[v1alpha1.LabelPoolNodeClassHash],
+	}
+	if metadata.Flavor == "" {
+		metadata.Flavor = labels[corev1.LabelInstanceTypeStable]
+	}
+	if metadata.Zone == "" {
+		metadata.Zone = labels[corev1.LabelTopologyZone]
+	}
+	return metadata
+}
+
+// poolSpec is the part of the NodeClass and NodePool template baked into a pool
+type poolSpec struct {
+	NodeClass     string            `json:"nodeClass"`
+	MonthlyBilled bool              `json:"monthlyBilled"`
+	AntiAffinity  bool              `json:"antiAffinity"`
+	Tags          map[string]string `json:"tags,omitempty"`
+	NodePool      string            `json:"nodePool,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Taints        []corev1.Taint    `json:"taints,omitempty"`
+}
+
+// poolTemplateHash returns a short hash of what a pool is created with: billing, placement and the
+// labels and taints of its template. It only depends on inputs fixed at creation, so the hash stamped
+// on a pool can be recomputed from the pool itself and compared to detect edits of its template.
+// Restricted labels are left out: they are managed by Karpenter and the provider, and flavor and
+// zone are already part of the pool name.
+func poolTemplateHash(nodeClass, nodePool string, monthlyBilled, antiAffinity bool, template *ovhclient.NodePoolTemplate) string {
+	spec := poolSpec{
+		NodeClass:     nodeClass,
+		MonthlyBilled: monthlyBilled,
+		AntiAffinity:  antiAffinity,
+		NodePool:      nodePool,
+	}
+	if template != nil {
+		for k, v := range template.Metadata.Labels {
+			if v1.IsRestrictedNodeLabel(k) {
+				continue
+			}
+			if spec.Labels == nil {
+				spec.Labels = make(map[string]string)
+			}
+			spec.Labels[k] = v
+		}
+		spec.Taints = append(spec.Taints, template.Spec.Taints...)
+		sort.Slice(spec.Taints, func(i, j int) bool {
+			return fmt.Sprintf("%s=%s:%s", spec.Taints[i].Key, spec.Taints[i].Value, spec.Taints[i].Effect) <
+				fmt.Sprintf("%s=%s:%s", spec.Taints[j].Key, spec.Taints[j].Value, spec.Taints[j].Effect)
+		})
+	}
+
+	return spec.hash()
+}
+
+// nodeClaimLabels returns the labels of a NodeClaim written into the template of its pool: the
+// NodePool template labels and the values of single-value requirements, as Karpenter sets them on
+// the NodeClaim once launched. Restricted labels are managed by Karpenter and the provider.
+func nodeClaimLabels(nodeClaim *v1.NodeClaim) map[string]string {
+	if nodeClaim == nil {
+		return nil
+	}
+	labels := make(map[string]string)
+	for key, requirement := range scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...) {
+		if !v1.IsRestrictedNodeLabel(key) && requirement.Operator() == corev1.NodeSelectorOpIn && requirement.Len() == 1 {
+			labels[key] = requirement.Values()[0]
+		}
+	}
+	for k, v := range nodeClaim.Labels {
+		if !v1.IsRestrictedNodeLabel(k) {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+// nodeClassHash returns a short hash of the NodeClass spec baked into a pool
+func nodeClassHash(nodeClass *v1alpha1.OVHNodeClass) string {
+	return nodeClassPoolSpec(nodeClass).hash()
+}
+
+func nodeClassPoolSpec(nodeClass *v1alpha1.OVHNodeClass) poolSpec {
+	return poolSpec{
+		NodeClass:     nodeClass.Name,
+		MonthlyBilled: nodeClass.Spec.MonthlyBilled,
+		AntiAffinity:  nodeClass.Spec.AntiAffinity,
+		Tags:          nodeClass.Spec.Tags,
+	}
+}
+
+func (s poolSpec) hash() string {
+	// Maps are marshalled with sorted keys, so the encoding is stable
+	data, err := json.Marshal(s)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:poolHashLength]
+}
@@ -0,0 +1,134 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis/v1alpha1"
+	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
+)
+
+func testTemplate(labels map[string]string, taints ...corev1.Taint) *ovhclient.NodePoolTemplate {
+	return &ovhclient.NodePoolTemplate{
+		Metadata: ovhclient.NodePoolTemplateMetadata{Labels: labels},
+		Spec:     ovhclient.NodePoolTemplateSpec{Taints: taints},
+	}
+}
+
+func TestPoolTemplateHash(t *testing.T) {
+	noSchedule := corev1.Taint{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+	noExecute := corev1.Taint{Key: "workload", Value: "batch", Effect: corev1.TaintEffectNoExecute}
+	base := poolTemplateHash("default", "default", false, false, testTemplate(map[string]string{"team": "a"}, noSchedule, noExecute))
+
+	tests := []struct {
+		name     string
+		hash     string
+		wantSame bool
+	}{
+		{
+			name:     "taints in another order",
+			hash:     poolTemplateHash("default", "default", false, false, testTemplate(map[string]string{"team": "a"}, noExecute, noSchedule)),
+			wantSame: true,
+		},
+		{
+			name: "restricted labels added",
+			hash: poolTemplateHash("default", "default", false, false, testTemplate(map[string]string{
+				"team":                            "a",
+				corev1.LabelInstanceTypeStable:    "b3-8",
+				v1alpha1.LabelInstanceCPU:         "2",
+				v1alpha1.LabelPoolHash:            "abcdef12",
+				v1.NodePoolLabelKey:               "default",
+				"karpenter.sh/registered":         "true",
+				v1alpha1.LabelPoolMonthlyBilled:   "false",
+				v1alpha1.LabelPoolNodeClassHash:   "12abcdef",
+				corev1.LabelTopologyZone:          "eu-west-par-a",
+				v1alpha1.LabelPoolNodeClass:       "default",
+				corev1.LabelArchStable:            "amd64",
+				v1.CapacityTypeLabelKey:           v1.CapacityTypeOnDemand,
+				corev1.LabelOSStable:              "linux",
+				v1alpha1.LabelInstanceGeneration:  "3",
+				v1alpha1.LabelInstanceSize:        "8",
+				v1alpha1.LabelInstanceFamily:      "b3",
+				v1alpha1.LabelInstanceMemory:      "8192",
+				v1alpha1.LabelInstanceCategory:    "b",
+				v1alpha1.LabelGPUCount:            "0",
+				corev1.LabelInstanceType:          "b3-8",
+				corev1.LabelFailureDomainBetaZone: "eu-west-par-a",
+			}, noSchedule, noExecute)),
+			wantSame: true,
+		},
+		{
+			name: "label value changed",
+			hash: poolTemplateHash("default", "default", false, false, testTemplate(map[string]string{"team": "b"}, noSchedule, noExecute)),
+		},
+		{
+			name: "label added",
+			hash: poolTemplateHash("default", "default", false, false, testTemplate(map[string]string{"team": "a", "env": "prod"}, noSchedule, noExecute)),
+		},
+		{
+			name: "taint removed",
+			hash: poolTemplateHash("default", "default", false, false, testTemplate(map[string]string{"team": "a"}, noSchedule)),
+		},
+		{
+			name: "other NodeClass",
+			hash: poolTemplateHash("gpu", "default", false, false, testTemplate(map[string]string{"team": "a"}, noSchedule, noExecute)),
+		},
+		{
+			name: "other NodePool",
+			hash: poolTemplateHash("default", "batch", false, false, testTemplate(map[string]string{"team": "a"}, noSchedule, noExecute)),
+		},
+		{
+			name: "monthly billed",
+			hash: poolTemplateHash("default", "default", true, false, testTemplate(map[string]string{"team": "a"}, noSchedule, noExecute)),
+		},
+		{
+			name: "anti-affinity",
+			hash: poolTemplateHash("default", "default", false, true, testTemplate(map[string]string{"team": "a"}, noSchedule, noExecute)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.hash) != poolHashLength {
+				t.Fatalf("hash %q is not %d characters long", tt.hash, poolHashLength)
+			}
+			if same := tt.hash == base; same != tt.wantSame {
+				t.Errorf("hash %s, base %s: same = %t, want %t", tt.hash, base, same, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestPoolTemplateHashMatchesCreatedPool(t *testing.T) {
+	nodeClass := &v1alpha1.OVHNodeClass{Spec: v1alpha1.OVHNodeClassSpec{Tags: map[string]string{"cost-center": "a"}, AntiAffinity: true}}
+	nodeClass.Name = "default"
+	template := testTemplate(map[string]string{"managed-by": "karpenter", "cost-center": "a"})
+	metadata := newPoolMetadata("b3-8", "eu-west-par-a", nodeClass, nil, template)
+
+	// The pool as read back from MKS, with the metadata labels added to its template
+	pool := &ovhclient.NodePool{FlavorName: "b3-8", AntiAffinity: true, Template: testTemplate(map[string]string{"managed-by": "karpenter", "cost-center": "a"})}
+	for k, v := range metadata.Labels() {
+		pool.Template.Metadata.Labels[k] = v
+	}
+	parsed := parsePoolMetadata(pool)
+	if got := poolTemplateHash(parsed.NodeClass, parsed.NodePool, pool.MonthlyBilled, pool.AntiAffinity, pool.Template); got != metadata.Hash {
+		t.Errorf("hash recomputed from the pool = %s, want %s", got, metadata.Hash)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
+)
+
+// poolScaleAction is what can be done with a desired-count change given the pool status
+type poolScaleAction int
+
+const (
+	// poolScaleApply means the pool accepts desired-count updates
+	poolScaleApply poolScaleAction = iota
+	// poolScaleQueue means MKS is still reconciling the pool and would reject the update
+	poolScaleQueue
+	// poolScaleReject means the pool is going away and will never accept the update
+	poolScaleReject
+)
+
+// poolScaleActionFor maps an MKS pool status to the handling of desired-count changes
+// READY pools are updated immediately. INSTALLING, UPDATING, RESIZING and REDEPLOYING pools
+// are still converging, so changes are queued until the pool is READY again. Unknown
+// statuses and ERROR are updated immediately and let the API decide.
+func poolScaleActionFor(status string) poolScaleAction {
+	switch status {
+	case PoolStatusDeleting:
+		return poolScaleReject
+	case PoolStatusInstalling, PoolStatusUpdating, PoolStatusResizing, PoolStatusRedeploying:
+		return poolScaleQueue
+	}
+	return poolScaleApply
+}
+
+// poolScaler accumulates desired-count changes per pool and applies them once the pool is READY
+// Changes requested while MKS reconciles a pool are merged into a single update.
+type poolScaler struct {
+	ovhClient *ovhclient.OVHClient
+
+	// flushMu serializes updates so that each one is computed from the latest desired count
+	flushMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]int // pool ID -> desired-count delta not applied yet
+}
+
+func newPoolScaler(ovhClient *ovhclient.OVHClient) *poolScaler {
+	return &poolScaler{
+		ovhClient: ovhClient,
+		pending:   make(map[string]int),
+	}
+}
+
+// Scale requests a desired-count change and applies it, with any queued change, if the pool is READY
+// It returns the pool as known after the call
+func (s *poolScaler) Scale(ctx context.Context, poolID string, delta int) (*ovhclient.NodePool, error) {
+	s.mu.Lock()
+	s.pending[poolID] += delta
+	s.mu.Unlock()
+
+	return s.Flush(ctx, poolID)
+}
+
+// Pending returns the desired-count delta queued for a pool
+func (s *poolScaler) Pending(poolID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending[poolID]
+}
+
+// Cancel withdraws a queued change that is no longer wanted, e.g. after a launch timed out
+// Changes already applied are left untouched
+func (s *poolScaler) Cancel(poolID string, delta int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending := s.pending[poolID]
+	switch {
+	case delta > 0 && pending > 0:
+		pending -= min(delta, pending)
+	case delta < 0 && pending < 0:
+		pending -= max(delta, pending)
+	}
+	if pending == 0 {
+		delete(s.pending, poolID)
+		return
+	}
+	s.pending[poolID] = pending
+}
+
+// Flush applies the queued change of a pool if its status allows it
+func (s *poolScaler) Flush(ctx context.Context, poolID string) (*ovhclient.NodePool, error) {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	pool, err := s.ovhClient.GetNodePool(ctx, poolID)
+	if err != nil {
+		if ovhclient.IsNotFound(err) {
+			s.drop(poolID)
+		}
+		return nil, fmt.Errorf("getting pool: %w", err)
+	}
+
+	switch poolScaleActionFor(pool.Status) {
+	case poolScaleReject:
+		s.drop(poolID)
+		return nil, fmt.Errorf("pool %s is %s", pool.Name, pool.Status)
+	case poolScaleQueue:
+		log.FromContext(ctx).V(1).Info("Pool is converging, queueing desired nodes change",
+			"poolID", poolID, "status", pool.Status, "pendingDelta", s.Pending(poolID))
+		return pool, nil
+	}
+
+	s.mu.Lock()
+	delta := s.pending[poolID]
+	delete(s.pending, poolID)
+	s.mu.Unlock()
+	if delta == 0 {
+		return pool, nil
+	}
+
+	desired := max(pool.DesiredNodes+delta, 0)
+	if _, err := s.ovhClient.UpdateNodePool(ctx, poolID, &ovhclient.UpdateNodePoolRequest{
+		DesiredNodes: desired,
+	}); err != nil {
+		// Keep the change queued for the next flush
+		s.mu.Lock()
+		s.pending[poolID] += delta
+		s.mu.Unlock()
+		RecordPoolOperation("scale", "error")
+		return nil, fmt.Errorf("scaling pool to %d nodes: %w", desired, err)
+	}
+	RecordPoolOperation("scale", "success")
+	log.FromContext(ctx).V(1).Info("Scaled pool", "poolID", poolID, "delta", delta, "desiredNodes", desired)
+
+	pool.DesiredNodes = desired
+	return pool, nil
+}
+
+// drop forgets the queued change of a pool
+func (s *poolScaler) drop(poolID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, poolID)
+}
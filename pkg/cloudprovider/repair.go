@@ -0,0 +1,122 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis/v1alpha1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+)
+
+// mksNodeErrorRepairPolicy repairs nodes MKS reports in ERROR, see the node health controller
+var mksNodeErrorRepairPolicy = cloudprovider.RepairPolicy{
+	ConditionType:      v1alpha1.NodeConditionTypeMKSNodeError,
+	ConditionStatus:    corev1.ConditionTrue,
+	TolerationDuration: 5 * time.Minute,
+}
+
+// repairRequestedRepairPolicy is the only policy Karpenter evaluates itself
+// Karpenter applies its repair policies to all nodes, so the node health controller evaluates the
+// policies of each node's NodeClass and requests the repair through the RepairRequested condition.
+var repairRequestedRepairPolicy = cloudprovider.RepairPolicy{
+	ConditionType:      v1alpha1.NodeConditionTypeRepairRequested,
+	ConditionStatus:    corev1.ConditionTrue,
+	TolerationDuration: 0,
+}
+
+// DefaultRepairPolicies are the node conditions repaired when no policies are configured
+var DefaultRepairPolicies = []cloudprovider.RepairPolicy{
+	{
+		ConditionType:      corev1.NodeReady,
+		ConditionStatus:    corev1.ConditionFalse,
+		TolerationDuration: 10 * time.Minute,
+	},
+	{
+		ConditionType:      corev1.NodeReady,
+		ConditionStatus:    corev1.ConditionUnknown,
+		TolerationDuration: 10 * time.Minute,
+	},
+	mksNodeErrorRepairPolicy,
+}
+
+// NodeClassRepairPolicies returns the repair policies a NodeClass sets, nil when it sets none
+// The MKS node error policy is added unless the NodeClass configures that condition itself.
+func NodeClassRepairPolicies(nodeClass *v1alpha1.OVHNodeClass) []cloudprovider.RepairPolicy {
+	if len(nodeClass.Spec.RepairPolicies) == 0 {
+		return nil
+	}
+	policies := make([]cloudprovider.RepairPolicy, 0, len(nodeClass.Spec.RepairPolicies)+1)
+	for _, policy := range nodeClass.Spec.RepairPolicies {
+		policies = append(policies, cloudprovider.RepairPolicy{
+			ConditionType:      policy.ConditionType,
+			ConditionStatus:    policy.ConditionStatus,
+			TolerationDuration: policy.TolerationDuration.Duration,
+		})
+	}
+	return withMKSNodeErrorPolicy(policies)
+}
+
+// ParseRepairPolicies parses a comma-separated list of Condition=Status:Toleration entries,
+// e.g. "Ready=False:10m,Ready=Unknown:10m,KernelDeadlock=True:15m"
+// The MKS node error policy is added unless the list configures that condition itself.
+func ParseRepairPolicies(spec string) ([]cloudprovider.RepairPolicy, error) {
+	var policies []cloudprovider.RepairPolicy
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		condition, rest, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid repair policy %q, expected Condition=Status:Toleration", entry)
+		}
+		status, toleration, ok := strings.Cut(rest, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid repair policy %q, expected Condition=Status:Toleration", entry)
+		}
+		switch corev1.ConditionStatus(status) {
+		case corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown:
+		default:
+			return nil, fmt.Errorf("invalid status %q in repair policy %q, expected True, False or Unknown", status, entry)
+		}
+		duration, err := time.ParseDuration(toleration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid toleration in repair policy %q: %w", entry, err)
+		}
+		policies = append(policies, cloudprovider.RepairPolicy{
+			ConditionType:      corev1.NodeConditionType(condition),
+			ConditionStatus:    corev1.ConditionStatus(status),
+			TolerationDuration: duration,
+		})
+	}
+	return withMKSNodeErrorPolicy(policies), nil
+}
+
+// withMKSNodeErrorPolicy adds the MKS node error policy unless the policies configure that condition
+func withMKSNodeErrorPolicy(policies []cloudprovider.RepairPolicy) []cloudprovider.RepairPolicy {
+	for _, policy := range policies {
+		if policy.ConditionType == v1alpha1.NodeConditionTypeMKSNodeError {
+			return policies
+		}
+	}
+	return append(policies, mksNodeErrorRepairPolicy)
+}
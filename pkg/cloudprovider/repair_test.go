@@ -0,0 +1,118 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis/v1alpha1"
+)
+
+func TestParseRepairPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []cloudprovider.RepairPolicy
+		wantErr bool
+	}{
+		{
+			name: "baseline",
+			spec: "Ready=False:10m,Ready=Unknown:10m,MKSNodeError=True:5m",
+			want: DefaultRepairPolicies,
+		},
+		{
+			name: "MKS node error policy added",
+			spec: " KernelDeadlock=True:15m , ",
+			want: []cloudprovider.RepairPolicy{
+				{ConditionType: "KernelDeadlock", ConditionStatus: corev1.ConditionTrue, TolerationDuration: 15 * time.Minute},
+				mksNodeErrorRepairPolicy,
+			},
+		},
+		{
+			name: "MKS node error toleration overridden",
+			spec: "MKSNodeError=True:1m",
+			want: []cloudprovider.RepairPolicy{
+				{ConditionType: v1alpha1.NodeConditionTypeMKSNodeError, ConditionStatus: corev1.ConditionTrue, TolerationDuration: time.Minute},
+			},
+		},
+		{
+			name: "empty",
+			spec: "",
+			want: []cloudprovider.RepairPolicy{mksNodeErrorRepairPolicy},
+		},
+		{name: "missing status", spec: "Ready:10m", wantErr: true},
+		{name: "missing toleration", spec: "Ready=False", wantErr: true},
+		{name: "invalid status", spec: "Ready=Maybe:10m", wantErr: true},
+		{name: "invalid toleration", spec: "Ready=False:ten", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRepairPolicies(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRepairPolicies(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRepairPolicies(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeClassRepairPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies []v1alpha1.RepairPolicy
+		want     []cloudprovider.RepairPolicy
+	}{
+		{
+			name: "none set",
+		},
+		{
+			name: "longer Ready toleration",
+			policies: []v1alpha1.RepairPolicy{
+				{ConditionType: corev1.NodeReady, ConditionStatus: corev1.ConditionFalse, TolerationDuration: metav1.Duration{Duration: 30 * time.Minute}},
+			},
+			want: []cloudprovider.RepairPolicy{
+				{ConditionType: corev1.NodeReady, ConditionStatus: corev1.ConditionFalse, TolerationDuration: 30 * time.Minute},
+				mksNodeErrorRepairPolicy,
+			},
+		},
+		{
+			name: "MKS node error toleration overridden",
+			policies: []v1alpha1.RepairPolicy{
+				{ConditionType: v1alpha1.NodeConditionTypeMKSNodeError, ConditionStatus: corev1.ConditionTrue, TolerationDuration: metav1.Duration{Duration: 20 * time.Minute}},
+			},
+			want: []cloudprovider.RepairPolicy{
+				{ConditionType: v1alpha1.NodeConditionTypeMKSNodeError, ConditionStatus: corev1.ConditionTrue, TolerationDuration: 20 * time.Minute},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeClass := &v1alpha1.OVHNodeClass{Spec: v1alpha1.OVHNodeClassSpec{RepairPolicies: tt.policies}}
+			if got := NodeClassRepairPolicies(nodeClass); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NodeClassRepairPolicies = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovhcloud
+
+import (
+	"sync"
+	"time"
+)
+
+// offeringKey identifies a flavor in a zone
+type offeringKey struct {
+	Flavor string
+	Zone   string
+}
+
+// UnavailableOfferings remembers flavor and zone pairs that recently failed to launch
+// Entries expire after the TTL so that capacity coming back is picked up again
+type UnavailableOfferings struct {
+	ttl time.Duration
+
+	mu      sync.RWMutex
+	entries map[offeringKey]time.Time // offering -> expiry
+}
+
+// NewUnavailableOfferings creates a new unavailable offerings cache
+func NewUnavailableOfferings(ttl time.Duration) *UnavailableOfferings {
+	return &UnavailableOfferings{
+		ttl:     ttl,
+		entries: make(map[offeringKey]time.Time),
+	}
+}
+
+// MarkUnavailable records that a flavor cannot currently be launched in a zone
+func (u *UnavailableOfferings) MarkUnavailable(flavor, zone, reason string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.entries[offeringKey{Flavor: flavor, Zone: zone}] = time.Now().Add(u.ttl)
+	RecordOfferingUnavailable(flavor, zone, reason)
+}
+
+// IsUnavailable returns true if a flavor recently failed to launch in a zone
+func (u *UnavailableOfferings) IsUnavailable(flavor, zone string) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	expiry, ok := u.entries[offeringKey{Flavor: flavor, Zone: zone}]
+	return ok && time.Now().Before(expiry)
+}
+
+// Len returns the number of offerings currently marked unavailable, pruning expired entries
+func (u *UnavailableOfferings) Len() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	now := time.Now()
+	for key, expiry := range u.entries {
+		if !now.Before(expiry) {
+			delete(u.entries, key)
+		}
+	}
+	return len(u.entries)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package allocatable
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ovhcloud "github.com/ovh/karpenter-provider-ovhcloud/pkg/cloudprovider"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	utilscontroller "sigs.k8s.io/karpenter/pkg/utils/controller"
+)
+
+// Controller records the allocatable resources of registered Karpenter Nodes per flavor
+// so that instance types reflect what MKS actually reserves on each node
+type Controller struct {
+	cache *ovhcloud.AllocatableCache
+}
+
+// NewController creates a new allocatable observation controller
+func NewController(cache *ovhcloud.AllocatableCache) *Controller {
+	return &Controller{
+		cache: cache,
+	}
+}
+
+func (c *Controller) Name() string {
+	return "node.allocatable"
+}
+
+func (c *Controller) Reconcile(ctx context.Context, node *corev1.Node) (reconcile.Result, error) {
+	logger := log.FromContext(ctx).WithValues("Node", node.Name)
+
+	flavor := node.Labels[corev1.LabelInstanceTypeStable]
+	if flavor == "" || !isReady(node) || len(node.Status.Allocatable) == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	if err := c.cache.Record(ctx, flavor, node.Status.Allocatable); err != nil {
+		return reconcile.Result{}, err
+	}
+	logger.V(1).Info("Recorded node allocatable", "flavor", flavor, "allocatable", node.Status.Allocatable)
+	return reconcile.Result{}, nil
+}
+
+// isReady returns true once kubelet has reported the node Ready, at which point allocatable is final
+func isReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named(c.Name()).
+		For(&corev1.Node{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			// Only Karpenter-launched nodes carry the NodePool label from the pool template
+			_, ok := o.GetLabels()[v1.NodePoolLabelKey]
+			return ok
+		}))).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: utilscontroller.LinearScaleReconciles(utilscontroller.CPUCount(ctx), 10, 100),
+		}).
+		Complete(reconcile.AsReconciler(m.GetClient(), c))
+}
@@ -0,0 +1,182 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interruption
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis/v1alpha1"
+	ovhcloud "github.com/ovh/karpenter-provider-ovhcloud/pkg/cloudprovider"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+)
+
+const (
+	// DefaultPollInterval is how often the sources are polled
+	DefaultPollInterval = time.Minute
+
+	// DefaultLeadTime is how long before a scheduled disruption the affected nodes are replaced
+	DefaultLeadTime = 30 * time.Minute
+)
+
+// Controller replaces Karpenter nodes ahead of host maintenance and instance migrations
+// Affected nodes are tainted so that no new pods land on them, and their NodeClaim is annotated. The
+// cloud provider then reports the NodeClaim as drifted, and Karpenter replaces the node like any drifted
+// node: within the NodePool disruption budgets, draining it within PodDisruptionBudgets.
+type Controller struct {
+	kubeClient client.Client
+	sources    []Source
+	interval   time.Duration
+	leadTime   time.Duration
+}
+
+// NewController creates a new interruption controller polling the given sources
+func NewController(kubeClient client.Client, interval, leadTime time.Duration, sources ...Source) *Controller {
+	return &Controller{
+		kubeClient: kubeClient,
+		sources:    sources,
+		interval:   interval,
+		leadTime:   leadTime,
+	}
+}
+
+// Start polls the sources every interval until the context is cancelled
+func (c *Controller) Start(ctx context.Context) error {
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithName("interruption"))
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection ensures a single replica taints nodes and annotates NodeClaims
+func (c *Controller) NeedLeaderElection() bool {
+	return true
+}
+
+// poll collects the events of all sources and handles those starting within the lead time
+func (c *Controller) poll(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	var events []Event
+	for _, source := range c.sources {
+		sourceEvents, err := source.Events(ctx)
+		if err != nil {
+			logger.V(1).Info("Failed to poll interruption source", "source", source.Name(), "error", err)
+			continue
+		}
+		events = append(events, sourceEvents...)
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	nodeClaims := &v1.NodeClaimList{}
+	if err := c.kubeClient.List(ctx, nodeClaims); err != nil {
+		logger.Error(err, "Failed to list NodeClaims for interruption handling")
+		return
+	}
+	byProviderID := make(map[string]*v1.NodeClaim, len(nodeClaims.Items))
+	for i := range nodeClaims.Items {
+		if providerID := nodeClaims.Items[i].Status.ProviderID; providerID != "" {
+			byProviderID[providerID] = &nodeClaims.Items[i]
+		}
+	}
+
+	for _, event := range events {
+		nodeClaim, ok := byProviderID[ovhcloud.ProviderPrefix+event.InstanceID]
+		if !ok || !nodeClaim.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if _, marked := nodeClaim.Annotations[v1alpha1.AnnotationInterruption]; marked {
+			continue
+		}
+		if !event.StartsAt.IsZero() && time.Until(event.StartsAt) > c.leadTime {
+			logger.V(1).Info("Interruption scheduled, waiting for the lead time",
+				"nodeClaim", nodeClaim.Name, "kind", event.Kind, "startsAt", event.StartsAt)
+			continue
+		}
+		if err := c.handle(ctx, nodeClaim, event); err != nil {
+			logger.Error(err, "Failed to handle interruption", "nodeClaim", nodeClaim.Name, "kind", event.Kind)
+			ovhcloud.RecordInterruptionEvent(event.Kind, "error")
+			continue
+		}
+		ovhcloud.RecordInterruptionEvent(event.Kind, "drift")
+	}
+}
+
+// handle taints the node of a NodeClaim and marks the NodeClaim for replacement
+func (c *Controller) handle(ctx context.Context, nodeClaim *v1.NodeClaim, event Event) error {
+	log.FromContext(ctx).Info("Replacing node ahead of interruption",
+		"nodeClaim", nodeClaim.Name,
+		"node", nodeClaim.Status.NodeName,
+		"kind", event.Kind,
+		"startsAt", event.StartsAt,
+		"reason", event.Reason)
+
+	if nodeClaim.Status.NodeName != "" {
+		if err := c.taintNode(ctx, nodeClaim.Status.NodeName, event); err != nil {
+			return err
+		}
+	}
+	stored := nodeClaim.DeepCopy()
+	if nodeClaim.Annotations == nil {
+		nodeClaim.Annotations = make(map[string]string)
+	}
+	nodeClaim.Annotations[v1alpha1.AnnotationInterruption] = event.Kind
+	if err := c.kubeClient.Patch(ctx, nodeClaim, client.MergeFrom(stored)); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("annotating NodeClaim: %w", err)
+	}
+	return nil
+}
+
+// taintNode adds the interruption taint to a node if it does not carry it yet
+func (c *Controller) taintNode(ctx context.Context, nodeName string, event Event) error {
+	node := &corev1.Node{}
+	if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == v1alpha1.TaintKeyInterruption {
+			return nil
+		}
+	}
+
+	stored := node.DeepCopy()
+	node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{
+		Key:    v1alpha1.TaintKeyInterruption,
+		Value:  event.Kind,
+		Effect: corev1.TaintEffectNoSchedule,
+	})
+	if err := c.kubeClient.Patch(ctx, node, client.MergeFrom(stored)); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("tainting node: %w", err)
+	}
+	return nil
+}
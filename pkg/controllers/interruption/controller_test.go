@@ -0,0 +1,115 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interruption
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis/v1alpha1"
+	ovhcloud "github.com/ovh/karpenter-provider-ovhcloud/pkg/cloudprovider"
+)
+
+// staticSource reports a fixed list of events
+type staticSource []Event
+
+func (s staticSource) Name() string {
+	return "static"
+}
+
+func (s staticSource) Events(context.Context) ([]Event, error) {
+	return s, nil
+}
+
+func TestPoll(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		// annotations are set on the NodeClaim before the poll
+		annotations map[string]string
+		wantMarked  bool
+		wantTainted bool
+	}{
+		{
+			name:        "migration started",
+			event:       Event{InstanceID: "instance", Kind: KindMigration},
+			wantMarked:  true,
+			wantTainted: true,
+		},
+		{
+			name:        "maintenance within the lead time",
+			event:       Event{InstanceID: "instance", Kind: KindMaintenance, StartsAt: time.Now().Add(10 * time.Minute)},
+			wantMarked:  true,
+			wantTainted: true,
+		},
+		{
+			name:  "maintenance after the lead time",
+			event: Event{InstanceID: "instance", Kind: KindMaintenance, StartsAt: time.Now().Add(2 * time.Hour)},
+		},
+		{
+			name:  "other instance",
+			event: Event{InstanceID: "other", Kind: KindMigration},
+		},
+		{
+			name:        "already marked",
+			event:       Event{InstanceID: "instance", Kind: KindMigration},
+			annotations: map[string]string{v1alpha1.AnnotationInterruption: KindMaintenance},
+			wantMarked:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			nodeClaim := &v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "default-abcde", Annotations: tt.annotations},
+				Status:     v1.NodeClaimStatus{ProviderID: ovhcloud.ProviderPrefix + "instance", NodeName: "node"},
+			}
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
+			kubeClient := fakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(nodeClaim, node).Build()
+
+			c := NewController(kubeClient, DefaultPollInterval, DefaultLeadTime, staticSource{tt.event})
+			c.poll(ctx)
+
+			// The NodeClaim is left to Karpenter's disruption, never deleted here
+			if err := kubeClient.Get(ctx, types.NamespacedName{Name: nodeClaim.Name}, nodeClaim); err != nil {
+				t.Fatalf("getting NodeClaim: %v", err)
+			}
+			if _, marked := nodeClaim.Annotations[v1alpha1.AnnotationInterruption]; marked != tt.wantMarked {
+				t.Errorf("NodeClaim marked = %v, want %v", marked, tt.wantMarked)
+			}
+
+			if err := kubeClient.Get(ctx, types.NamespacedName{Name: node.Name}, node); err != nil {
+				t.Fatalf("getting node: %v", err)
+			}
+			tainted := false
+			for _, taint := range node.Spec.Taints {
+				tainted = tainted || taint.Key == v1alpha1.TaintKeyInterruption
+			}
+			if tainted != tt.wantTainted {
+				t.Errorf("node tainted = %v, want %v", tainted, tt.wantTainted)
+			}
+		})
+	}
+}
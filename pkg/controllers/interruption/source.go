@@ -0,0 +1,126 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interruption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
+)
+
+// Event kinds
+const (
+	KindMaintenance = "maintenance"
+	KindMigration   = "migration"
+)
+
+// Event announces a disruption of an instance
+type Event struct {
+	InstanceID string `json:"instanceId"`
+	Kind       string `json:"kind"`
+	// StartsAt is when the disruption starts; zero when it already started or is not scheduled
+	StartsAt time.Time `json:"startsAt,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// Source reports the instances about to be disrupted
+type Source interface {
+	Name() string
+	Events(ctx context.Context) ([]Event, error)
+}
+
+// InstanceStatusSource reports instances the OVH API shows as being migrated to another host
+// The status is only MIGRATING once the migration started, so this source is reactive: the node is
+// replaced after the migration began, not ahead of it. Scheduled events come from an HTTPSource.
+type InstanceStatusSource struct {
+	ovhClient *ovhclient.OVHClient
+}
+
+// NewInstanceStatusSource creates a source reading the instance statuses of the cluster region
+func NewInstanceStatusSource(ovhClient *ovhclient.OVHClient) *InstanceStatusSource {
+	return &InstanceStatusSource{
+		ovhClient: ovhClient,
+	}
+}
+
+func (s *InstanceStatusSource) Name() string {
+	return "instances"
+}
+
+// Events returns a migration event for each MIGRATING instance
+func (s *InstanceStatusSource) Events(ctx context.Context) ([]Event, error) {
+	instances, err := s.ovhClient.ListInstances(ctx, s.ovhClient.GetRegion())
+	if err != nil {
+		return nil, err
+	}
+	var events []Event
+	for _, instance := range instances {
+		if instance.Status == "MIGRATING" {
+			events = append(events, Event{
+				InstanceID: instance.ID,
+				Kind:       KindMigration,
+				Reason:     fmt.Sprintf("instance %s is %s", instance.Name, instance.Status),
+			})
+		}
+	}
+	return events, nil
+}
+
+// HTTPSource reads scheduled events from an HTTP endpoint returning a JSON list of events,
+// e.g. a maintenance calendar exporter or a local fake of the OVH API
+type HTTPSource struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewHTTPSource creates a source polling the given URL
+func NewHTTPSource(url string) *HTTPSource {
+	return &HTTPSource{
+		url:        url,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (s *HTTPSource) Name() string {
+	return "http"
+}
+
+// Events fetches and decodes the events served at the URL
+func (s *HTTPSource) Events(ctx context.Context) ([]Event, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching events: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching events: unexpected status %d", resp.StatusCode)
+	}
+	var events []Event
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, fmt.Errorf("decoding events: %w", err)
+	}
+	return events, nil
+}
@@ -19,6 +19,7 @@ package nodeclass
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/awslabs/operatorpkg/status"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -31,18 +32,24 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis/v1alpha1"
+	ovhclient "github.com/ovh/karpenter-provider-ovhcloud/pkg/client"
 	utilscontroller "sigs.k8s.io/karpenter/pkg/utils/controller"
 )
 
+// apiAvailabilityResyncPeriod is how often the APIAvailable condition follows the circuit breaker
+const apiAvailabilityResyncPeriod = 30 * time.Second
+
 // Controller reconciles OVHNodeClass resources
 type Controller struct {
 	kubeClient client.Client
+	ovhClient  *ovhclient.OVHClient
 }
 
 // NewController creates a new OVHNodeClass controller
-func NewController(kubeClient client.Client) *Controller {
+func NewController(kubeClient client.Client, ovhClient *ovhclient.OVHClient) *Controller {
 	return &Controller{
 		kubeClient: kubeClient,
+		ovhClient:  ovhClient,
 	}
 }
 
@@ -59,8 +66,18 @@ func (c *Controller) Reconcile(ctx context.Context, nodeClass *v1alpha1.OVHNodeC
 		nodeClass.StatusConditions().SetFalse(status.ConditionReady, "ValidationFailed", err.Error())
 		logger.Error(err, "OVHNodeClass validation failed")
 	} else {
+		if !nodeClass.StatusConditions().IsTrue(status.ConditionReady) {
+			logger.Info("OVHNodeClass is ready")
+		}
 		nodeClass.StatusConditions().SetTrue(status.ConditionReady)
-		logger.Info("OVHNodeClass is ready")
+	}
+
+	// Surface the OVH API circuit breaker so that stalled launches are explained on the NodeClass
+	if state := c.ovhClient.CircuitState(); state == ovhclient.CircuitClosed {
+		nodeClass.StatusConditions().SetTrue(v1alpha1.ConditionTypeAPIAvailable)
+	} else {
+		nodeClass.StatusConditions().SetFalse(v1alpha1.ConditionTypeAPIAvailable, "CircuitBreakerOpen",
+			fmt.Sprintf("OVH API circuit breaker is %s after repeated failures, launches fail fast", state))
 	}
 
 	// Only patch if status changed
@@ -76,7 +93,7 @@ func (c *Controller) Reconcile(ctx context.Context, nodeClass *v1alpha1.OVHNodeC
 		}
 	}
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: apiAvailabilityResyncPeriod}, nil
 }
 
 // validateNodeClass validates the OVHNodeClass configuration
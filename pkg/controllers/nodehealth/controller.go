@@ -0,0 +1,213 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodehealth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis/v1alpha1"
+	ovhcloud "github.com/ovh/karpenter-provider-ovhcloud/pkg/cloudprovider"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	nodeutils "sigs.k8s.io/karpenter/pkg/utils/node"
+)
+
+// Controller mirrors the MKS status of Karpenter nodes into the MKSNodeError Node condition, and
+// requests the repair of nodes whose conditions match the repair policies of their NodeClass
+// Karpenter applies its repair policies to all nodes alike, so the policies are evaluated here and
+// Karpenter repairs the nodes carrying the RepairRequested condition, within the same safety limits
+// as any other unhealthy condition.
+type Controller struct {
+	kubeClient client.Client
+	inventory  *ovhcloud.Inventory
+	interval   time.Duration
+	// policies apply to the nodes of NodeClasses that set no repair policies
+	policies []cloudprovider.RepairPolicy
+}
+
+// NewController creates a new node health controller reading MKS node statuses from the inventory
+func NewController(kubeClient client.Client, inventory *ovhcloud.Inventory, interval time.Duration, policies []cloudprovider.RepairPolicy) *Controller {
+	return &Controller{
+		kubeClient: kubeClient,
+		inventory:  inventory,
+		interval:   interval,
+		policies:   policies,
+	}
+}
+
+// Start syncs the Node conditions every interval until the context is cancelled
+func (c *Controller) Start(ctx context.Context) error {
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithName("nodehealth"))
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.sync(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection ensures a single replica patches Node conditions
+func (c *Controller) NeedLeaderElection() bool {
+	return true
+}
+
+// sync sets the MKSNodeError and RepairRequested conditions of the Nodes of all launched NodeClaims
+func (c *Controller) sync(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	nodeClaims := &v1.NodeClaimList{}
+	if err := c.kubeClient.List(ctx, nodeClaims); err != nil {
+		logger.Error(err, "Failed to list NodeClaims for node health")
+		return
+	}
+	mksAvailable := true
+	nodeClassPolicies := make(map[string][]cloudprovider.RepairPolicy)
+	for _, nodeClaim := range nodeClaims.Items {
+		if nodeClaim.Status.NodeName == "" || !strings.HasPrefix(nodeClaim.Status.ProviderID, ovhcloud.ProviderPrefix) {
+			continue
+		}
+		node := &corev1.Node{}
+		if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: nodeClaim.Status.NodeName}, node); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				logger.Error(err, "Failed to get node", "node", nodeClaim.Status.NodeName)
+			}
+			continue
+		}
+		stored := node.DeepCopy()
+
+		if mksAvailable {
+			instanceID := strings.TrimPrefix(nodeClaim.Status.ProviderID, ovhcloud.ProviderPrefix)
+			mksNode, _, err := c.inventory.FindInstance(ctx, instanceID)
+			if err != nil {
+				// The OVH API is unavailable, keep the MKS conditions as they are
+				logger.V(1).Info("Cannot read MKS node statuses", "error", err)
+				mksAvailable = false
+			} else if mksNode != nil {
+				setMKSNodeErrorCondition(node, mksNode.Status)
+			}
+		}
+
+		policies, ok := nodeClassPolicies[nodeClassName(&nodeClaim)]
+		if !ok {
+			policies = c.repairPolicies(ctx, &nodeClaim)
+			nodeClassPolicies[nodeClassName(&nodeClaim)] = policies
+		}
+		setRepairRequestedCondition(node, policies, time.Now())
+
+		if equality.Semantic.DeepEqual(stored.Status.Conditions, node.Status.Conditions) {
+			continue
+		}
+		// Strategic merge only sends the changed conditions, leaving the kubelet-managed ones untouched
+		if err := c.kubeClient.Status().Patch(ctx, node, client.StrategicMergeFrom(stored)); client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "Failed to patch node conditions", "node", node.Name)
+			continue
+		}
+		logger.Info("Updated node health conditions", "node", node.Name,
+			"mksNodeError", nodeutils.GetCondition(node, v1alpha1.NodeConditionTypeMKSNodeError).Status,
+			"repairRequested", nodeutils.GetCondition(node, v1alpha1.NodeConditionTypeRepairRequested).Status)
+	}
+}
+
+// repairPolicies returns the repair policies of the NodeClass of a NodeClaim, or the controller's
+// when the NodeClass sets none or cannot be read
+func (c *Controller) repairPolicies(ctx context.Context, nodeClaim *v1.NodeClaim) []cloudprovider.RepairPolicy {
+	name := nodeClassName(nodeClaim)
+	if name == "" {
+		return c.policies
+	}
+	nodeClass := &v1alpha1.OVHNodeClass{}
+	if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: name}, nodeClass); err != nil {
+		log.FromContext(ctx).V(1).Info("Cannot read NodeClass repair policies", "nodeClass", name, "error", err)
+		return c.policies
+	}
+	if policies := ovhcloud.NodeClassRepairPolicies(nodeClass); policies != nil {
+		return policies
+	}
+	return c.policies
+}
+
+func nodeClassName(nodeClaim *v1.NodeClaim) string {
+	if nodeClaim.Spec.NodeClassRef == nil {
+		return ""
+	}
+	return nodeClaim.Spec.NodeClassRef.Name
+}
+
+// setMKSNodeErrorCondition sets the MKSNodeError condition of a Node from its MKS status
+func setMKSNodeErrorCondition(node *corev1.Node, mksStatus string) {
+	status, reason := corev1.ConditionFalse, "MKSNodeHealthy"
+	if mksStatus == ovhcloud.NodeStatusError {
+		status, reason = corev1.ConditionTrue, "MKSNodeError"
+	}
+	setCondition(node, v1alpha1.NodeConditionTypeMKSNodeError, status, reason, fmt.Sprintf("MKS reports the node as %s", mksStatus))
+}
+
+// setRepairRequestedCondition requests the repair of a Node with a condition that matched a repair
+// policy for longer than its toleration, and withdraws the request once none does
+func setRepairRequestedCondition(node *corev1.Node, policies []cloudprovider.RepairPolicy, now time.Time) {
+	for _, policy := range policies {
+		condition := nodeutils.GetCondition(node, policy.ConditionType)
+		if condition.Status != policy.ConditionStatus || now.Before(condition.LastTransitionTime.Add(policy.TolerationDuration)) {
+			continue
+		}
+		setCondition(node, v1alpha1.NodeConditionTypeRepairRequested, corev1.ConditionTrue, string(policy.ConditionType),
+			fmt.Sprintf("%s has been %s for longer than %s", policy.ConditionType, policy.ConditionStatus, policy.TolerationDuration))
+		return
+	}
+	setCondition(node, v1alpha1.NodeConditionTypeRepairRequested, corev1.ConditionFalse, "Healthy", "No condition matches a repair policy")
+}
+
+// setCondition updates a condition of a Node when its status changed
+// Healthy Nodes that never had the condition are left without it
+func setCondition(node *corev1.Node, conditionType corev1.NodeConditionType, status corev1.ConditionStatus, reason, message string) {
+	existing := nodeutils.GetCondition(node, conditionType)
+	if existing.Status == status || (existing.Type == "" && status == corev1.ConditionFalse) {
+		return
+	}
+
+	now := metav1.Now()
+	condition := corev1.NodeCondition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastHeartbeatTime:  now,
+		LastTransitionTime: now,
+	}
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == conditionType {
+			node.Status.Conditions[i] = condition
+			return
+		}
+	}
+	node.Status.Conditions = append(node.Status.Conditions, condition)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodehealth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	nodeutils "sigs.k8s.io/karpenter/pkg/utils/node"
+
+	"github.com/ovh/karpenter-provider-ovhcloud/pkg/apis/v1alpha1"
+	ovhcloud "github.com/ovh/karpenter-provider-ovhcloud/pkg/cloudprovider"
+)
+
+func TestSetRepairRequestedCondition(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		// conditions are the node conditions, with their age
+		conditions map[corev1.NodeConditionType]corev1.ConditionStatus
+		age        time.Duration
+		// requested is the status of an existing RepairRequested condition
+		requested corev1.ConditionStatus
+		want      corev1.ConditionStatus
+	}{
+		{
+			name:       "healthy",
+			conditions: map[corev1.NodeConditionType]corev1.ConditionStatus{corev1.NodeReady: corev1.ConditionTrue},
+			age:        time.Hour,
+		},
+		{
+			name:       "not ready within the toleration",
+			conditions: map[corev1.NodeConditionType]corev1.ConditionStatus{corev1.NodeReady: corev1.ConditionFalse},
+			age:        5 * time.Minute,
+		},
+		{
+			name:       "not ready past the toleration",
+			conditions: map[corev1.NodeConditionType]corev1.ConditionStatus{corev1.NodeReady: corev1.ConditionUnknown},
+			age:        15 * time.Minute,
+			want:       corev1.ConditionTrue,
+		},
+		{
+			name:       "MKS node error past the toleration",
+			conditions: map[corev1.NodeConditionType]corev1.ConditionStatus{corev1.NodeReady: corev1.ConditionTrue, v1alpha1.NodeConditionTypeMKSNodeError: corev1.ConditionTrue},
+			age:        6 * time.Minute,
+			want:       corev1.ConditionTrue,
+		},
+		{
+			name:       "recovered",
+			conditions: map[corev1.NodeConditionType]corev1.ConditionStatus{corev1.NodeReady: corev1.ConditionTrue},
+			age:        time.Hour,
+			requested:  corev1.ConditionTrue,
+			want:       corev1.ConditionFalse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{}
+			for conditionType, status := range tt.conditions {
+				node.Status.Conditions = append(node.Status.Conditions, corev1.NodeCondition{
+					Type:               conditionType,
+					Status:             status,
+					LastTransitionTime: metav1.NewTime(now.Add(-tt.age)),
+				})
+			}
+			if tt.requested != "" {
+				node.Status.Conditions = append(node.Status.Conditions, corev1.NodeCondition{Type: v1alpha1.NodeConditionTypeRepairRequested, Status: tt.requested})
+			}
+
+			setRepairRequestedCondition(node, ovhcloud.DefaultRepairPolicies, now)
+			if got := nodeutils.GetCondition(node, v1alpha1.NodeConditionTypeRepairRequested).Status; got != tt.want {
+				t.Errorf("RepairRequested = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepairPolicies(t *testing.T) {
+	gpu := &v1alpha1.OVHNodeClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+		Spec: v1alpha1.OVHNodeClassSpec{RepairPolicies: []v1alpha1.RepairPolicy{
+			{ConditionType: corev1.NodeReady, ConditionStatus: corev1.ConditionFalse, TolerationDuration: metav1.Duration{Duration: 30 * time.Minute}},
+		}},
+	}
+	defaultNodeClass := &v1alpha1.OVHNodeClass{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	kubeClient := fakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(gpu, defaultNodeClass).Build()
+	c := NewController(kubeClient, nil, time.Minute, ovhcloud.DefaultRepairPolicies)
+
+	tests := []struct {
+		name      string
+		nodeClass string
+		// wantReady is the toleration of Ready=False for the nodes of the NodeClass
+		wantReady time.Duration
+	}{
+		{name: "NodeClass policies", nodeClass: "gpu", wantReady: 30 * time.Minute},
+		{name: "NodeClass without policies", nodeClass: "default", wantReady: 10 * time.Minute},
+		{name: "missing NodeClass", nodeClass: "missing", wantReady: 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeClaim := &v1.NodeClaim{Spec: v1.NodeClaimSpec{NodeClassRef: &v1.NodeClassReference{Name: tt.nodeClass}}}
+			var got time.Duration
+			for _, policy := range c.repairPolicies(context.Background(), nodeClaim) {
+				if policy.ConditionType == corev1.NodeReady && policy.ConditionStatus == corev1.ConditionFalse {
+					got = policy.TolerationDuration
+				}
+			}
+			if got != tt.wantReady {
+				t.Errorf("Ready=False toleration = %v, want %v", got, tt.wantReady)
+			}
+		})
+	}
+}